@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+// Annotation colors
+const (
+	AnnotationColorStandard1 = "STANDARD1"
+	AnnotationColorStandard2 = "STANDARD2"
+	AnnotationColorStandard3 = "STANDARD3"
+	AnnotationColorStandard4 = "STANDARD4"
+	AnnotationColorStandard5 = "STANDARD5"
+	AnnotationColorStandard6 = "STANDARD6"
+	AnnotationColorStandard7 = "STANDARD7"
+	AnnotationColorStandard8 = "STANDARD8"
+	AnnotationColorStandard9 = "STANDARD9"
+)
+
+// Response types
+
+// AnnotationScopeMetric represents a metric an annotation is scoped to
+type AnnotationScopeMetric struct {
+	ID            string `json:"id,omitempty"`
+	ComponentType string `json:"componentType,omitempty"`
+}
+
+// AnnotationScopeFilter represents a filter an annotation is scoped to
+type AnnotationScopeFilter struct {
+	ID            string   `json:"id,omitempty"`
+	Operator      string   `json:"operator,omitempty"`
+	DimensionType string   `json:"dimensionType,omitempty"`
+	Terms         []string `json:"terms,omitempty"`
+	ComponentType string   `json:"componentType,omitempty"`
+}
+
+// AnnotationScope represents the scope of an annotation
+type AnnotationScope struct {
+	Metrics *[]AnnotationScopeMetric `json:"metrics,omitempty"`
+	Filters *[]AnnotationScopeFilter `json:"filters,omitempty"`
+}
+
+// Annotation represents an annotation
+type Annotation struct {
+	ID                string           `json:"id,omitempty"`
+	Name              string           `json:"name,omitempty"`
+	Description       string           `json:"description,omitempty"`
+	DateRange         *DateInterval    `json:"dateRange,omitempty"`
+	Color             string           `json:"color,omitempty"`
+	ApplyToAllReports *bool            `json:"applyToAllReports,omitempty"`
+	Scope             *AnnotationScope `json:"scope,omitempty"`
+	RSID              string           `json:"rsid,omitempty"`
+	ReportSuiteName   string           `json:"reportSuiteName,omitempty"`
+	Owner             *Owner           `json:"owner,omitempty"`
+	Tags              *[]Tag           `json:"tags,omitempty"`
+	Modified          string           `json:"modified,omitempty"`
+	Created           string           `json:"created,omitempty"`
+}
+
+// Annotations represents a page of annotations
+type Annotations struct {
+	Content          *[]Annotation `json:"content,omitempty"`
+	Number           int           `json:"number"`
+	Size             int           `json:"size"`
+	NumberOfElements int           `json:"numberOfElements"`
+	TotalElements    int           `json:"totalElements"`
+	PreviousPage     bool          `json:"previousPage"`
+	FirstPage        bool          `json:"firstPage"`
+	NextPage         bool          `json:"nextPage"`
+	LastPage         bool          `json:"lastPage"`
+	Sort             *[]Sort       `json:"sort,omitempty"`
+	TotalPages       int           `json:"totalPages"`
+}
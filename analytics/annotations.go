@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AnnotationsService handles annotations.
+// Analytics docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations
+type AnnotationsService struct {
+	client *Client
+}
+
+// GetAll returns a list of annotations that match the given filters.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations/getAnnotations
+func (s *AnnotationsService) GetAll(rsids, locale, filterByIDs string, filterByDateRange *DateInterval,
+	limit, page int64, sortDirection, sortProperty string,
+	expansion, includeType []string) (*Annotations, error) {
+
+	var params = map[string]string{}
+	if rsids != "" {
+		params["rsids"] = rsids
+	}
+	if locale != "" {
+		params["locale"] = locale
+	}
+	if filterByIDs != "" {
+		params["filterByIds"] = filterByIDs
+	}
+	if filterByDateRange != nil {
+		params["filterByDateRange"] = filterByDateRange.String()
+	}
+	params["limit"] = strconv.FormatInt(limit, 10)
+	params["page"] = strconv.FormatInt(page, 10)
+	if sortDirection != "" {
+		params["sortDirection"] = sortDirection
+	}
+	if sortProperty != "" {
+		params["sortProperty"] = sortProperty
+	}
+	if len(expansion) > 0 {
+		params["expansion"] = strings.Join(expansion[:], ",")
+	}
+	if len(includeType) > 0 {
+		params["includeType"] = strings.Join(includeType[:], ",")
+	}
+
+	var data Annotations
+	err := s.client.get("/annotations", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetByID returns a single annotation.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations/getAnnotation
+func (s *AnnotationsService) GetByID(id, locale string, expansion []string) (*Annotation, error) {
+	var params = map[string]string{}
+	if locale != "" {
+		params["locale"] = locale
+	}
+	if len(expansion) > 0 {
+		params["expansion"] = strings.Join(expansion[:], ",")
+	}
+
+	var data Annotation
+	err := s.client.get(fmt.Sprintf("/annotations/%s", id), params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Create creates a new annotation.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations/createAnnotation
+func (s *AnnotationsService) Create(annotation *Annotation) (*Annotation, error) {
+	reqJSON, err := json.Marshal(annotation)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data Annotation
+	err = s.client.create("/annotations", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Update updates an existing annotation.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations/updateAnnotation
+func (s *AnnotationsService) Update(id string, annotation *Annotation) (*Annotation, error) {
+	reqJSON, err := json.Marshal(annotation)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data Annotation
+	err = s.client.put(fmt.Sprintf("/annotations/%s", id), map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Delete deletes an annotation.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/annotations/deleteAnnotation
+func (s *AnnotationsService) Delete(id string) error {
+	return s.client.delete(fmt.Sprintf("/annotations/%s", id), map[string]string{}, nil, nil)
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestAnnotationsGetAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/annotations"
+
+	raw, err := ioutil.ReadFile("./testdata/Annotations.GetAll.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"rsids":             "amc.aem.prod",
+			"locale":            "en_US",
+			"filterByIds":       "1,2",
+			"filterByDateRange": "2020-09-01T00:00:00.000/2020-10-01T00:00:00.000",
+			"limit":             "10",
+			"page":              "0",
+			"sortDirection":     "ASC",
+			"sortProperty":      "dateRange",
+			"expansion":         "a,b",
+			"includeType":       "all",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	dateRange := &analytics.DateInterval{
+		Start: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	annotations, err := testClient.Annotations.GetAll("amc.aem.prod", "en_US", "1,2", dateRange, 10, 0, "ASC", "dateRange", []string{"a", "b"}, []string{"all"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*annotations.Content) != 2 {
+		t.Errorf("Expected %d annotations but got %d", 2, len(*annotations.Content))
+		return
+	}
+
+	if got := (*annotations.Content)[1].DateRange.Start; !got.Equal(time.Date(2020, 9, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected annotation date range start %v", got)
+	}
+}
+
+func TestAnnotationsGetAllError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Annotations.GetAll("", "en_US", "", nil, 10, 0, "", "", []string{}, []string{})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestAnnotationsGetByID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/annotations/5f4e1a2b3c4d5e6f7a8b9c01"
+
+	raw, err := ioutil.ReadFile("./testdata/Annotations.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"locale":    "en_US",
+			"expansion": "a,b",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	annotation, err := testClient.Annotations.GetByID("5f4e1a2b3c4d5e6f7a8b9c01", "en_US", []string{"a", "b"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if annotation.ID != "5f4e1a2b3c4d5e6f7a8b9c01" {
+		t.Errorf("Expected annotation with ID=5f4e1a2b3c4d5e6f7a8b9c01 but got ID=%s", annotation.ID)
+	}
+
+	if annotation.Color != analytics.AnnotationColorStandard3 {
+		t.Errorf("Expected annotation with Color=%s but got Color=%s", analytics.AnnotationColorStandard3, annotation.Color)
+	}
+
+	if len(*annotation.Scope.Filters) != 1 {
+		t.Errorf("Expected %d scope filters but got %d", 1, len(*annotation.Scope.Filters))
+	}
+}
+
+func TestAnnotationsGetByIDError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/annotations/5f4e1a2b3c4d5e6f7a8b9c01", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := testClient.Annotations.GetByID("5f4e1a2b3c4d5e6f7a8b9c01", "en_US", []string{})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestAnnotationsCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/annotations"
+
+	req, err := ioutil.ReadFile("./testdata/Annotations.Create.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var annotation analytics.Annotation
+	if err := json.Unmarshal(req, &annotation); err != nil {
+		t.Error(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile("./testdata/Annotations.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, req)
+		fmt.Fprint(w, string(raw))
+	})
+
+	created, err := testClient.Annotations.Create(&annotation)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if created.ID != "5f4e1a2b3c4d5e6f7a8b9c01" {
+		t.Errorf("Expected annotation with ID=5f4e1a2b3c4d5e6f7a8b9c01 but got ID=%s", created.ID)
+	}
+}
+
+func TestAnnotationsCreateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/annotations", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := testClient.Annotations.Create(&analytics.Annotation{Name: "Release 2.4.0"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestAnnotationsUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/annotations/5f4e1a2b3c4d5e6f7a8b9c01"
+
+	raw, err := ioutil.ReadFile("./testdata/Annotations.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, apiEndpoint)
+		// unset fields are not sent to keep their values
+		testRequestBody(t, r, []byte(`{"name": "Release 2.4.0", "color": "STANDARD3"}`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	annotation, err := testClient.Annotations.Update("5f4e1a2b3c4d5e6f7a8b9c01", &analytics.Annotation{
+		Name:  "Release 2.4.0",
+		Color: analytics.AnnotationColorStandard3,
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if annotation.Name != "Release 2.4.0" {
+		t.Errorf("Expected annotation with Name=Release 2.4.0 but got Name=%s", annotation.Name)
+	}
+}
+
+func TestAnnotationApplyToAllReports(t *testing.T) {
+	data, err := json.Marshal(&analytics.Annotation{Name: "Release", ApplyToAllReports: analytics.Bool(false)})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if string(data) != `{"name":"Release","applyToAllReports":false}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+func TestAnnotationsDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/annotations/5f4e1a2b3c4d5e6f7a8b9c01"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.Annotations.Delete("5f4e1a2b3c4d5e6f7a8b9c01")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestAnnotationsDeleteError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/annotations/5f4e1a2b3c4d5e6f7a8b9c01", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	err := testClient.Annotations.Delete("5f4e1a2b3c4d5e6f7a8b9c01")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestParseDateInterval(t *testing.T) {
+	d, err := analytics.ParseDateInterval("2020-09-01T00:00:00/2020-09-01T23:59:59.999")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if got := d.String(); got != "2020-09-01T00:00:00.000/2020-09-01T23:59:59.999" {
+		t.Errorf("Date interval: %s, want %s", got, "2020-09-01T00:00:00.000/2020-09-01T23:59:59.999")
+	}
+}
+
+func TestParseDateIntervalMalformed(t *testing.T) {
+	for _, s := range []string{"", "2020-09-01T00:00:00", "2020-09-01/2020-09-02", "2020-09-01T00:00:00/x"} {
+		if _, err := analytics.ParseDateInterval(s); err == nil {
+			t.Errorf("Expected error for %q but got none", s)
+		}
+	}
+}
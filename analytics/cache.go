@@ -0,0 +1,152 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"container/list"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores responses of GET requests.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached value of the key, if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the value of the key for the duration of ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	// DeletePrefix deletes all values with keys starting with prefix.
+	DeletePrefix(prefix string)
+}
+
+// LRUCache is an in-memory Cache which evicts the least recently used entries.
+type LRUCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// lruEntry represents a LRUCache entry
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns a new LRUCache holding up to maxEntries entries.
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Get returns the cached value of the key, if present and not expired.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores the value of the key for the duration of ttl.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// DeletePrefix deletes all values with keys starting with prefix.
+func (c *LRUCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(element)
+		}
+	}
+}
+
+// Len returns the number of entries, including expired entries not yet evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove removes an element, the caller must hold the lock.
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
+
+// cacheKey returns the cache key of a GET request, made of company ID, path and sorted params.
+func cacheKey(companyID, path string, params map[string]string) string {
+	q := url.Values{}
+	for key, value := range params {
+		q.Set(key, value)
+	}
+	// Encode sorts by key
+	return cachePrefix(companyID, path) + "?" + q.Encode()
+}
+
+// cachePrefix returns the cache key prefix of a path.
+func cachePrefix(companyID, path string) string {
+	return companyID + "/" + strings.Trim(path, "/")
+}
+
+// cacheTTL returns the TTL of a path, the longest matching prefix of ttls takes precedence over defaultTTL.
+func cacheTTL(path string, defaultTTL time.Duration, ttls map[string]time.Duration) time.Duration {
+	path = "/" + strings.Trim(path, "/")
+
+	ttl := defaultTTL
+	longest := -1
+	for prefix, prefixTTL := range ttls {
+		prefix = "/" + strings.Trim(prefix, "/")
+		matches := path == prefix || strings.HasPrefix(path, prefix+"/") || prefix == "/"
+		if matches && len(prefix) > longest {
+			ttl = prefixTTL
+			longest = len(prefix)
+		}
+	}
+	return ttl
+}
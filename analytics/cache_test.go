@@ -0,0 +1,304 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func newCachedTestClient(t *testing.T, cache analytics.Cache, ttl time.Duration, ttls map[string]time.Duration) *analytics.Client {
+	config := *testConfig
+	config.Cache = cache
+	config.CacheTTL = ttl
+	config.CacheTTLs = ttls
+
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return client
+}
+
+func TestClientCache(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := map[string]int{}
+	testMux.HandleFunc(baseURL+"/", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		fmt.Fprint(w, `[{"id": "variables/page"}]`)
+	})
+
+	client := newCachedTestClient(t, analytics.NewLRUCache(100), 0, map[string]time.Duration{
+		"/dimensions": time.Hour,
+	})
+
+	for i := 0; i < 3; i++ {
+		dimensions, err := client.Dimensions.GetAll("amc.aem.prod", "en_US", false, false, false, []string{})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if (*dimensions)[0].ID != "variables/page" {
+			t.Errorf("Unexpected dimensions %v", *dimensions)
+		}
+		client.Metrics.GetAll("amc.aem.prod", "en_US", false, []string{})
+	}
+
+	if calls[baseURL+"/dimensions"] != 1 {
+		t.Errorf("Expected %d dimension requests but got %d", 1, calls[baseURL+"/dimensions"])
+	}
+	if calls[baseURL+"/metrics"] != 3 {
+		t.Errorf("Expected %d metric requests but got %d", 3, calls[baseURL+"/metrics"])
+	}
+
+	// different params are cached separately
+	client.Dimensions.GetAll("other.rsid", "en_US", false, false, false, []string{})
+	if calls[baseURL+"/dimensions"] != 2 {
+		t.Errorf("Expected %d dimension requests but got %d", 2, calls[baseURL+"/dimensions"])
+	}
+
+	client.InvalidateCache("/dimensions")
+	client.Dimensions.GetAll("amc.aem.prod", "en_US", false, false, false, []string{})
+	if calls[baseURL+"/dimensions"] != 3 {
+		t.Errorf("Expected %d dimension requests but got %d", 3, calls[baseURL+"/dimensions"])
+	}
+}
+
+func TestClientCacheInvalidatedByChanges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc(baseURL+"/dateranges", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"content": []}`)
+	})
+	testMux.HandleFunc(baseURL+"/dateranges/57a9ad685fe707f55ffb68f5", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	client := newCachedTestClient(t, analytics.NewLRUCache(100), time.Hour, nil)
+
+	client.DateRanges.GetAll("", "", 10, 0, []string{}, []string{})
+	client.DateRanges.GetAll("", "", 10, 0, []string{}, []string{})
+	if calls != 1 {
+		t.Errorf("Expected %d requests but got %d", 1, calls)
+	}
+
+	err := client.DateRanges.Delete("57a9ad685fe707f55ffb68f5")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	client.DateRanges.GetAll("", "", 10, 0, []string{}, []string{})
+	if calls != 2 {
+		t.Errorf("Expected %d requests but got %d", 2, calls)
+	}
+}
+
+func TestClientCacheKeptByReports(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := map[string]int{}
+	testMux.HandleFunc(baseURL+"/reports/topItems", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		fmt.Fprint(w, `{"rows": []}`)
+	})
+	testMux.HandleFunc(baseURL+"/dateranges", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			calls[r.URL.Path]++
+		}
+		fmt.Fprint(w, `{"content": []}`)
+	})
+	testMux.HandleFunc(baseURL+"/dateranges/57a9ad685fe707f55ffb68f5", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		fmt.Fprint(w, `{"id": "57a9ad685fe707f55ffb68f5"}`)
+	})
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"rows": []}`)
+	})
+
+	client := newCachedTestClient(t, analytics.NewLRUCache(100), time.Hour, nil)
+
+	getAll := func() {
+		client.Reports.TopItems("amc.aem.prod", "variables/page", "", "", 10, 0)
+		client.DateRanges.GetAll("", "", 10, 0, []string{}, []string{})
+		client.DateRanges.GetByID("57a9ad685fe707f55ffb68f5", "", []string{})
+	}
+	getAll()
+
+	// running a report does not change any resources
+	_, err := client.Reports.Run(&analytics.RankedRequest{ReportSuiteID: "amc.aem.prod", Dimension: "variables/page"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	getAll()
+	for path, n := range calls {
+		if n != 1 {
+			t.Errorf("Expected %d requests of %s but got %d", 1, path, n)
+		}
+	}
+
+	// creating a date range only invalidates the lists of date ranges
+	_, err = client.DateRanges.Create(&analytics.DateRange{Name: "New"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	getAll()
+	if calls[baseURL+"/dateranges"] != 2 || calls[baseURL+"/dateranges/57a9ad685fe707f55ffb68f5"] != 1 || calls[baseURL+"/reports/topItems"] != 1 {
+		t.Errorf("Unexpected requests %v", calls)
+	}
+}
+
+func TestClientCacheErrorsNotCached(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client := newCachedTestClient(t, analytics.NewLRUCache(100), time.Hour, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Users.GetCurrent(); err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected %d requests but got %d", 2, calls)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := analytics.NewLRUCache(2)
+
+	cache.Set("a", []byte("1"), time.Hour)
+	cache.Set("b", []byte("2"), time.Hour)
+	cache.Get("a")
+	cache.Set("c", []byte("3"), time.Hour)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected least recently used entry to be evicted")
+	}
+	if value, ok := cache.Get("a"); !ok || string(value) != "1" {
+		t.Errorf("Expected entry a=1 but got %s (%v)", value, ok)
+	}
+
+	cache.Set("d", []byte("4"), -time.Second)
+	if _, ok := cache.Get("d"); ok {
+		t.Errorf("Expected expired entry to be missing")
+	}
+
+	cache.DeletePrefix("c")
+	if cache.Len() != 1 {
+		t.Errorf("Expected %d entries but got %d", 1, cache.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aa-client-go-cache")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := analytics.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	cache.Set("company/dimensions?rsid=a", []byte(`[1]`), time.Hour)
+	cache.Set("company/metrics?rsid=a", []byte(`[2]`), time.Hour)
+	cache.Set("company/expired", []byte(`[3]`), -time.Second)
+
+	// a new cache on the same directory sees the entries
+	cache, err = analytics.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if value, ok := cache.Get("company/dimensions?rsid=a"); !ok || string(value) != "[1]" {
+		t.Errorf("Expected entry [1] but got %s (%v)", value, ok)
+	}
+	if _, ok := cache.Get("company/expired"); ok {
+		t.Errorf("Expected expired entry to be missing")
+	}
+
+	cache.DeletePrefix("company/dimensions")
+	if _, ok := cache.Get("company/dimensions?rsid=a"); ok {
+		t.Errorf("Expected deleted entry to be missing")
+	}
+	if _, ok := cache.Get("company/metrics?rsid=a"); !ok {
+		t.Errorf("Expected entry to be present")
+	}
+}
+
+func TestDiskCacheDeletePrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aa-client-go-cache")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := analytics.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	keys := []string{
+		"company/dateranges?limit=10",
+		"company/dateranges/1?expansion=tags",
+		"company/dateranges/1/tags?",
+		"company/dateranges/2?",
+		"company/segments?limit=10",
+	}
+	for _, key := range keys {
+		cache.Set(key, []byte(`[]`), time.Hour)
+	}
+	// an unreadable file outside of the invalidated paths is not touched
+	other := filepath.Join(dir, "other.json")
+	if err := ioutil.WriteFile(other, []byte("{"), 0600); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	present := func(want ...bool) {
+		t.Helper()
+		for i, key := range keys {
+			if _, ok := cache.Get(key); ok != want[i] {
+				t.Errorf("Expected %s present %v but got %v", key, want[i], ok)
+			}
+		}
+	}
+
+	cache.DeletePrefix("company/dateranges/1/")
+	present(true, true, false, true, true)
+	cache.DeletePrefix("company/dateranges?")
+	present(false, true, false, true, true)
+	cache.DeletePrefix("company/dateranges/")
+	present(false, false, false, false, true)
+
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected %s to be kept but got %v", other, err)
+	}
+}
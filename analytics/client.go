@@ -12,6 +12,8 @@ governing permissions and limitations under the License.
 package analytics
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,23 +21,50 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // Config holds configuration values
 type Config struct {
-	HTTPClient  *http.Client
-	BaseURL     string
-	ClientID    string
-	OrgID       string
-	AccessToken string
-	CompanyID   string
+	HTTPClient   *http.Client
+	BaseURL      string
+	DiscoveryURL string
+	ClientID     string
+	OrgID        string
+	AccessToken  string
+	TokenSource  TokenSource
+	CompanyID    string
+
+	// RateLimiter optionally limits the request rate of the client.
+	RateLimiter *RateLimiter
+	// MaxConcurrency optionally limits the number of concurrent requests of the client.
+	MaxConcurrency int
+
+	// Cache optionally caches the responses of GET requests.
+	Cache Cache
+	// CacheTTL is the default time to live of cached responses, 0 disables caching by default.
+	CacheTTL time.Duration
+	// CacheTTLs overrides the CacheTTL per path prefix, e.g. "/dimensions" or "/metrics".
+	CacheTTLs map[string]time.Duration
+
+	// ReportCache optionally caches the results of historical reports, see ReportsService.Run.
+	ReportCache Cache
+	// ReportCacheTTL is the time to live of cached reports, defaults to 30 days.
+	ReportCacheTTL time.Duration
+
+	// Middlewares optionally wrap each API request, e.g. to log requests or to set custom headers.
+	// The first middleware sees the request first.
+	Middlewares []Middleware
+
+	// Logger optionally logs requests and responses at debug level, secrets are redacted.
+	Logger Logger
 }
 
 // Auth holds authentication information
 type auth struct {
 	imsClientID    string
 	imsOrgID       string
-	imsAccessToken string
+	imsTokenSource TokenSource
 	companyID      string
 }
 
@@ -45,15 +74,30 @@ type Client struct {
 	httpClient *http.Client
 	baseURL    *url.URL
 	auth       *auth
+	throttle   *throttle
+	cache      Cache
+	cacheTTL   time.Duration
+	cacheTTLs  map[string]time.Duration
+
+	reportCache    Cache
+	reportCacheTTL time.Duration
+
+	middlewares []Middleware
+	logger      Middleware
+	ctx         context.Context
 
 	// Services used for communicating to different parts of the API.
+	Annotations       *AnnotationsService
 	CalculatedMetrics *CalculatedMetricsService
 	Collections       *CollectionsService
 	DateRanges        *DateRangesService
 	Dimensions        *DimensionsService
 	Metrics           *MetricsService
+	Projects          *ProjectsService
 	Reports           *ReportsService
 	Segments          *SegmentsService
+	Shares            *SharesService
+	Tags              *TagsService
 	Users             *UsersService
 }
 
@@ -74,7 +118,7 @@ func NewClient(config *Config) (*Client, error) {
 	auth := &auth{
 		imsClientID:    config.ClientID,
 		imsOrgID:       config.OrgID,
-		imsAccessToken: config.AccessToken,
+		imsTokenSource: newTokenSource(config),
 		companyID:      config.CompanyID,
 	}
 	err = verifyAuth(auth)
@@ -86,20 +130,63 @@ func NewClient(config *Config) (*Client, error) {
 		httpClient: httpClient,
 		baseURL:    parsedBaseURL,
 		auth:       auth,
+		throttle:   newThrottle(config),
+		cache:      config.Cache,
+		cacheTTL:   config.CacheTTL,
+		cacheTTLs:  config.CacheTTLs,
+
+		reportCache:    config.ReportCache,
+		reportCacheTTL: config.ReportCacheTTL,
+
+		middlewares: append([]Middleware{}, config.Middlewares...),
+	}
+	if config.Logger != nil {
+		c.logger = loggerMiddleware(config.Logger)
+	}
+	if c.reportCacheTTL <= 0 {
+		c.reportCacheTTL = defaultReportCacheTTL
 	}
 
-	c.CalculatedMetrics = &CalculatedMetricsService{client: c}
-	c.Collections = &CollectionsService{client: c}
-	c.DateRanges = &DateRangesService{client: c}
-	c.Dimensions = &DimensionsService{client: c}
-	c.Metrics = &MetricsService{client: c}
-	c.Reports = &ReportsService{client: c}
-	c.Segments = &SegmentsService{client: c}
-	c.Users = &UsersService{client: c}
+	c.initServices()
 
 	return c, nil
 }
 
+// initServices initializes the services of the client.
+func (client *Client) initServices() {
+	client.Annotations = &AnnotationsService{client: client}
+	client.CalculatedMetrics = &CalculatedMetricsService{client: client}
+	client.Collections = &CollectionsService{client: client}
+	client.DateRanges = &DateRangesService{client: client}
+	client.Dimensions = &DimensionsService{client: client}
+	client.Metrics = &MetricsService{client: client}
+	client.Projects = &ProjectsService{client: client}
+	client.Reports = &ReportsService{client: client}
+	client.Segments = &SegmentsService{client: client}
+	client.Shares = &SharesService{client: client}
+	client.Tags = &TagsService{client: client}
+	client.Users = &UsersService{client: client}
+}
+
+// WithContext returns a copy of the client which sends its requests with ctx, e.g. to cancel requests or to pass a trace context.
+// Canceling ctx also stops waiting for rate and concurrency limits.
+// The copy shares rate limits and caches with the client, middlewares added with Use only apply to the copy.
+func (client *Client) WithContext(ctx context.Context) *Client {
+	c := *client
+	c.ctx = ctx
+	c.middlewares = append([]Middleware{}, client.middlewares...)
+	c.initServices()
+	return &c
+}
+
+// context returns the context of the client's requests.
+func (client *Client) context() context.Context {
+	if client.ctx == nil {
+		return context.Background()
+	}
+	return client.ctx
+}
+
 // verifyBaseURL verifies the specified URL
 func verifyBaseURL(baseURL string) (*url.URL, error) {
 	if strings.HasSuffix(baseURL, "/") {
@@ -126,7 +213,7 @@ func verifyAuth(auth *auth) error {
 	if auth.imsClientID == "" {
 		return fmt.Errorf("missing ClientID")
 	}
-	if auth.imsAccessToken == "" {
+	if auth.imsTokenSource == nil {
 		return fmt.Errorf("missing AccessToken")
 	}
 	if auth.imsOrgID == "" {
@@ -143,77 +230,249 @@ func (client *Client) GetBaseURL() string {
 	return client.baseURL.String()
 }
 
+// Stats returns the request statistics of the client, e.g. the time spent waiting for rate and concurrency limits.
+func (client *Client) Stats() ClientStats {
+	return client.throttle.stats()
+}
+
+// Use appends middlewares to the client.
+// It must not be called concurrently with requests.
+func (client *Client) Use(middlewares ...Middleware) {
+	client.middlewares = append(client.middlewares, middlewares...)
+}
+
+// InvalidateCache removes the cached responses of the path and all paths below, e.g. "/dimensions".
+func (client *Client) InvalidateCache(path string) {
+	if client.cache == nil {
+		return
+	}
+	prefix := cachePrefix(client.auth.companyID, path)
+	client.cache.DeletePrefix(prefix + "?")
+	client.cache.DeletePrefix(prefix + "/")
+}
+
 // get is a convenience method to send an HTTP GET request
 func (client *Client) get(path string, params map[string]string, body io.Reader, model interface{}) error {
 	return apiRequest(client, http.MethodGet, path, params, body, model)
 }
 
-// post is a convenience method to send an HTTP POST request
+// post is a convenience method to send an HTTP POST request which does not change resources, e.g. to run a report
 func (client *Client) post(path string, params map[string]string, body io.Reader, model interface{}) error {
 	return apiRequest(client, http.MethodPost, path, params, body, model)
 }
 
+// create is a convenience method to send an HTTP POST request which creates a resource in the collection at path.
+// The cached lists of the collection are invalidated.
+func (client *Client) create(path string, params map[string]string, body io.Reader, model interface{}) error {
+	err := apiRequest(client, http.MethodPost, path, params, body, model)
+	if err == nil {
+		client.invalidateList(path)
+	}
+	return err
+}
+
+// put is a convenience method to send an HTTP PUT request.
+// The cached responses of the resource and the lists of its collection are invalidated.
+func (client *Client) put(path string, params map[string]string, body io.Reader, model interface{}) error {
+	err := apiRequest(client, http.MethodPut, path, params, body, model)
+	if err == nil {
+		client.invalidateResource(path)
+	}
+	return err
+}
+
+// delete is a convenience method to send an HTTP DELETE request.
+// The cached responses of the resource and the lists of its collection are invalidated.
+func (client *Client) delete(path string, params map[string]string, body io.Reader, model interface{}) error {
+	err := apiRequest(client, http.MethodDelete, path, params, body, model)
+	if err == nil {
+		client.invalidateResource(path)
+	}
+	return err
+}
+
+// invalidateList removes the cached responses of the path itself, e.g. the lists of "/dateranges" with any params.
+func (client *Client) invalidateList(path string) {
+	if client.cache == nil {
+		return
+	}
+	client.cache.DeletePrefix(cachePrefix(client.auth.companyID, path) + "?")
+}
+
+// invalidateResource removes the cached responses of a resource and the lists of its collection,
+// e.g. "/dateranges/<ID>" and "/dateranges".
+func (client *Client) invalidateResource(path string) {
+	client.InvalidateCache(path)
+	trimmed := strings.Trim(path, "/")
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		client.invalidateList(trimmed[:i])
+	}
+}
+
 // apiRequest does a HTTP request and unmarshals the response into the specified model.
+// The response body is discarded if model is nil.
+// GET responses are cached if the client has a cache, see create, put and delete for the invalidation by changes.
 func apiRequest(client *Client, method, path string, params map[string]string, body io.Reader, model interface{}) error {
+	var ttl time.Duration
+	if client.cache != nil && method == http.MethodGet {
+		ttl = cacheTTL(path, client.cacheTTL, client.cacheTTLs)
+	}
+
+	if ttl <= 0 {
+		_, err := doAPIRequest(client, method, path, params, body, model)
+		return err
+	}
+
+	key := cacheKey(client.auth.companyID, path, params)
+	if cached, ok := client.cache.Get(key); ok {
+		if model == nil {
+			return nil
+		}
+		return json.Unmarshal(cached, &model)
+	}
+
+	data, err := doAPIRequest(client, method, path, params, body, model)
+	if err == nil {
+		client.cache.Set(key, data, ttl)
+	}
+	return err
+}
+
+// doAPIRequest does a HTTP request, unmarshals the response into the specified model and returns the response body.
+func doAPIRequest(client *Client, method, path string, params map[string]string, body io.Reader, model interface{}) ([]byte, error) {
 	resp, respErr := request(client, method, path, params, body)
 	if respErr != nil {
-		return respErr
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, respErr
 	}
 	defer resp.Body.Close()
 
 	bodyStr, bodyErr := ioutil.ReadAll(resp.Body)
 	if bodyErr != nil {
-		return bodyErr
+		return nil, bodyErr
+	}
+
+	if model == nil {
+		return bodyStr, nil
 	}
 
 	jsonErr := json.Unmarshal(bodyStr, &model)
 	if jsonErr != nil {
-		return jsonErr
+		return nil, jsonErr
 	}
 
-	return jsonErr
+	return bodyStr, jsonErr
 }
 
 // request does a HTTP request with the specified client, method, path, params and body.
+// The request passes through the middlewares of the client.
 func request(client *Client, method, path string, params map[string]string, body io.Reader) (*http.Response, error) {
+	accessToken, err := client.auth.imsTokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyBytes []byte
+	if body != nil {
+		bodyBytes, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Set required headers
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("Content-Type", "application/json")
+	header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	header.Set("x-api-key", client.auth.imsClientID)
+	// discovery requests are not scoped to an organization or company
+	if client.auth.imsOrgID != "" {
+		header.Set("x-gw-ims-org-id", client.auth.imsOrgID)
+	}
+	if client.auth.companyID != "" {
+		header.Set("x-proxy-global-company-id", client.auth.companyID)
+	}
+
+	req := &Request{
+		Context: client.context(),
+		Method:  method,
+		Path:    path,
+		Params:  params,
+		Body:    bodyBytes,
+		Header:  header,
+	}
+
+	// the logger is the innermost middleware to log the request as sent
+	handler := client.send
+	if client.logger != nil {
+		handler = client.logger(handler)
+	}
+	handler = chainMiddlewares(handler, client.middlewares)
+	res, err := handler(req)
+	if err != nil {
+		if res != nil {
+			res.Body.Close()
+		}
+		return nil, err
+	}
+
+	err = checkResponse(res)
+	if err != nil {
+		// we return the response in case the caller wants to inspect it
+		return res, err
+	}
+
+	return res, err
+}
+
+// send sends a request, it is the innermost Handler of the middleware chain.
+func (client *Client) send(req *Request) (*http.Response, error) {
 	// ensure path has prefix
+	path := req.Path
 	if strings.HasPrefix(path, "/") {
 		path = strings.TrimPrefix(path, "/")
 	}
 
-	// URL format is <API_URL>/<COMPANY_ID>/<PATH>
+	// URL format is <API_URL>/<COMPANY_ID>/<PATH>, or <DISCOVERY_URL>/<PATH> for discovery
 	// join <baseURL.Path>/<CompanyID>/<Path>
 	rel := &url.URL{Path: fmt.Sprintf("%s/%s/%s", client.baseURL.Path, client.auth.companyID, path)}
+	if client.auth.companyID == "" {
+		rel.Path = fmt.Sprintf("%s/%s", client.baseURL.Path, path)
+	}
 	u := client.baseURL.ResolveReference(rel)
-	addParams(u, params)
+	addParams(u, req.Params)
 
-	httpClient := client.httpClient
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
 
-	req, err := http.NewRequest(method, u.String(), body)
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
+	httpReq.Header = req.Header
 
-	// Set required headers
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", client.auth.imsAccessToken))
-	req.Header.Set("x-api-key", client.auth.imsClientID)
-	req.Header.Set("x-gw-ims-org-id", client.auth.imsOrgID)
-	req.Header.Set("x-proxy-global-company-id", client.auth.companyID)
-
-	res, err := httpClient.Do(req)
+	// wait for rate and concurrency limits, the slot is released once the response body is closed
+	release, err := client.throttle.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	err = checkResponse(res)
+	res, err := client.httpClient.Do(httpReq)
 	if err != nil {
-		// we return the response in case the caller wants to inspect it
-		return res, err
+		release()
+		return nil, err
 	}
-
-	return res, err
+	res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+	return res, nil
 }
 
 // addParams adds the specified params to the URL.
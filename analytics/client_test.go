@@ -13,13 +13,17 @@ package analytics_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/adobe/aa-client-go/analytics"
 )
@@ -219,3 +223,232 @@ func TestNewClientTrimSuffix(t *testing.T) {
 		t.Fatalf("unexpected trailing slash")
 	}
 }
+
+func TestNewClientTokenSource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer renewedToken" {
+			t.Errorf("Authorization header: %s, want %s", got, "Bearer renewedToken")
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	config := *testConfig
+	config.AccessToken = ""
+	config.TokenSource = analytics.StaticTokenSource("renewedToken")
+
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	_, err = client.Users.GetCurrent()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestClientMaxConcurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		fmt.Fprint(w, `{}`)
+	})
+
+	config := *testConfig
+	config.MaxConcurrency = 2
+
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Users.GetCurrent(); err != nil {
+				t.Errorf("Error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxActive > 2 {
+		t.Errorf("Expected at most %d concurrent requests but got %d", 2, maxActive)
+	}
+
+	stats := client.Stats()
+	if stats.Requests != 6 {
+		t.Errorf("Expected %d requests but got %d", 6, stats.Requests)
+	}
+	if stats.ConcurrencyWait == 0 {
+		t.Errorf("Expected concurrency wait but got none")
+	}
+}
+
+func TestClientMaxConcurrencyReleasedOnError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	config := *testConfig
+	config.MaxConcurrency = 1
+
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Users.GetCurrent(); err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	config := *testConfig
+	config.RateLimiter = analytics.NewRateLimiter(50, 1)
+
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Users.GetCurrent(); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	}
+
+	stats := client.Stats()
+	if stats.RateLimitWait < 20*time.Millisecond {
+		t.Errorf("Expected rate limit wait of about 40ms but got %v", stats.RateLimitWait)
+	}
+}
+
+func TestClientWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Context().Value(contextKey{}); got != nil {
+			t.Errorf("Unexpected server context value %v", got)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	var got interface{}
+	testClient.Use(func(next analytics.Handler) analytics.Handler {
+		return func(req *analytics.Request) (*http.Response, error) {
+			got = req.Context.Value(contextKey{})
+			return next(req)
+		}
+	})
+
+	_, err := testClient.WithContext(ctx).Users.GetCurrent()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if got != "value" {
+		t.Errorf("Expected context value %q but got %v", "value", got)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = testClient.WithContext(canceled).Users.GetCurrent()
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestClientWithContextThrottled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	entered := make(chan struct{}, 1)
+	block := make(chan struct{})
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-block
+		fmt.Fprint(w, `{}`)
+	})
+
+	// the second request waits for the concurrency slot of the first one
+	config := *testConfig
+	config.MaxConcurrency = 1
+	client, _ := analytics.NewClient(&config)
+	done := make(chan error)
+	go func() {
+		_, err := client.Users.GetCurrent()
+		done <- err
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.WithContext(ctx).Users.GetCurrent()
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the canceled request to return but it waited %v", elapsed)
+	}
+	close(block)
+	if err := <-done; err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	// the second request waits for the rate limiter
+	config = *testConfig
+	config.RateLimiter = analytics.NewRateLimiter(0.01, 1)
+	client, _ = analytics.NewClient(&config)
+	_, err = client.Users.GetCurrent()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = client.WithContext(ctx).Users.GetCurrent()
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the canceled request to return but it waited %v", elapsed)
+	}
+}
+
+type contextKey struct{}
@@ -11,8 +11,28 @@ governing permissions and limitations under the License.
 
 package analytics
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Common/Shared types
 
+// Component types used by tagging and sharing
+const (
+	ComponentTypeSegment          = "segment"
+	ComponentTypeCalculatedMetric = "calculatedMetric"
+	ComponentTypeDateRange        = "dateRange"
+	ComponentTypeProject          = "project"
+)
+
+// Bool returns a pointer to the passed value, e.g. to set optional bool fields.
+func Bool(v bool) *bool {
+	return &v
+}
+
 // Owner represents an owner
 type Owner struct {
 	ID    int    `json:"id"`
@@ -42,3 +62,57 @@ type Tag struct {
 	Description string             `json:"description,omitempty"`
 	Components  *[]TaggedComponent `json:"components,omitempty"`
 }
+
+// dateIntervalLayout is the timestamp layout of date intervals used by the API
+const dateIntervalLayout = "2006-01-02T15:04:05.000"
+
+// DateInterval represents a date range in the ISO 8601 interval format used by the API,
+// e.g. "2020-08-30T00:00:00.000/2020-09-26T23:59:59.999".
+// Timestamps are in the time zone of the report suite and are serialized without offset.
+type DateInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseDateInterval parses a date range in the ISO 8601 interval format.
+func ParseDateInterval(s string) (*DateInterval, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed date interval %q", s)
+	}
+
+	start, err := time.Parse("2006-01-02T15:04:05", parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed date interval start: %v", err)
+	}
+	end, err := time.Parse("2006-01-02T15:04:05", parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed date interval end: %v", err)
+	}
+	return &DateInterval{Start: start, End: end}, nil
+}
+
+// String returns the date range in the ISO 8601 interval format.
+func (d DateInterval) String() string {
+	return d.Start.Format(dateIntervalLayout) + "/" + d.End.Format(dateIntervalLayout)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d DateInterval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *DateInterval) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDateInterval(s)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
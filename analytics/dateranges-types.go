@@ -13,9 +13,10 @@ package analytics
 
 // Response types
 
-// DateRangeDefinition represents a date range definition
-type DateRangeDefinition struct {
-}
+// DateRangeDefinition represents a date range definition.
+// The definition is kept as a generic JSON object so that fixed and rolling
+// date ranges can be created and read back without loss.
+type DateRangeDefinition map[string]interface{}
 
 // DateRange represents a date range
 type DateRange struct {
@@ -12,6 +12,7 @@ governing permissions and limitations under the License.
 package analytics
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -68,3 +69,43 @@ func (s *DateRangesService) GetByID(id, locale string, expansion []string) (*Dat
 	}
 	return &data, err
 }
+
+// Create creates a new date range.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/dateranges/createDateRange
+func (s *DateRangesService) Create(dateRange *DateRange) (*DateRange, error) {
+	reqJSON, err := json.Marshal(dateRange)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data DateRange
+	err = s.client.create("/dateranges", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Update updates an existing date range.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/dateranges/updateDateRange
+func (s *DateRangesService) Update(id string, dateRange *DateRange) (*DateRange, error) {
+	reqJSON, err := json.Marshal(dateRange)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data DateRange
+	err = s.client.put(fmt.Sprintf("/dateranges/%s", id), map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Delete deletes a date range.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/dateranges/deleteDateRange
+func (s *DateRangesService) Delete(id string) error {
+	return s.client.delete(fmt.Sprintf("/dateranges/%s", id), map[string]string{}, nil, nil)
+}
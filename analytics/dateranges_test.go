@@ -12,10 +12,13 @@ governing permissions and limitations under the License.
 package analytics_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
 )
 
 func TestDateRangesGetAll(t *testing.T) {
@@ -112,3 +115,131 @@ func TestDateRangesGetByIDError(t *testing.T) {
 		t.Errorf("Expected error but got none")
 	}
 }
+
+func TestDateRangesCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/dateranges"
+
+	req, err := ioutil.ReadFile("./testdata/DateRanges.Create.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var dateRange analytics.DateRange
+	json.Unmarshal(req, &dateRange)
+
+	raw, err := ioutil.ReadFile("./testdata/DateRanges.Create.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, req)
+		fmt.Fprint(w, string(raw))
+	})
+
+	daterange, err := testClient.DateRanges.Create(&dateRange)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if daterange.ID != "5f3ab2f1c4e5a35d2e8f9a11" {
+		t.Errorf("Expected date range with ID=5f3ab2f1c4e5a35d2e8f9a11 but got ID=%s", daterange.ID)
+	}
+
+	if (*daterange.Definition)["dateRange"] != "2020-08-30T00:00:00.000/2020-09-26T23:59:59.999" {
+		t.Errorf("Unexpected date range definition %v", *daterange.Definition)
+	}
+}
+
+func TestDateRangesCreateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/dateranges", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := testClient.DateRanges.Create(&analytics.DateRange{Name: "FY21 P01"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestDateRangesUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/dateranges/5f3ab2f1c4e5a35d2e8f9a11"
+
+	raw, err := ioutil.ReadFile("./testdata/DateRanges.Update.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(`{"name": "FY21 P01 (updated)"}`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	daterange, err := testClient.DateRanges.Update("5f3ab2f1c4e5a35d2e8f9a11", &analytics.DateRange{Name: "FY21 P01 (updated)"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if daterange.Name != "FY21 P01 (updated)" {
+		t.Errorf("Expected date range with Name=FY21 P01 (updated) but got Name=%s", daterange.Name)
+	}
+}
+
+func TestDateRangesUpdateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/dateranges/5f3ab2f1c4e5a35d2e8f9a11", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.DateRanges.Update("5f3ab2f1c4e5a35d2e8f9a11", &analytics.DateRange{Name: "FY21 P01"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestDateRangesDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/dateranges/5f3ab2f1c4e5a35d2e8f9a11"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.DateRanges.Delete("5f3ab2f1c4e5a35d2e8f9a11")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestDateRangesDeleteError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/dateranges/5f3ab2f1c4e5a35d2e8f9a11", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	err := testClient.DateRanges.Delete("5f3ab2f1c4e5a35d2e8f9a11")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
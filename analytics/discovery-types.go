@@ -0,0 +1,34 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+// Response types
+
+// DiscoveryCompany represents a global company accessible by the user
+type DiscoveryCompany struct {
+	GlobalCompanyID    string `json:"globalCompanyId,omitempty"`
+	CompanyName        string `json:"companyName,omitempty"`
+	APIRateLimitPolicy string `json:"apiRateLimitPolicy,omitempty"`
+	DPC                string `json:"dpc,omitempty"`
+}
+
+// DiscoveryOrg represents an IMS organization and its global companies
+type DiscoveryOrg struct {
+	IMSOrgID  string              `json:"imsOrgId,omitempty"`
+	Companies *[]DiscoveryCompany `json:"companies,omitempty"`
+}
+
+// Discovery represents the IMS organizations and global companies accessible by the user
+type Discovery struct {
+	IMSUserID string          `json:"imsUserId,omitempty"`
+	IMSOrgs   *[]DiscoveryOrg `json:"imsOrgs,omitempty"`
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Discover returns the IMS organizations and global companies accessible with the access token of the config.
+// The config does not need a CompanyID. Unless set, the discovery URL is derived from the BaseURL,
+// e.g. https://analytics.adobe.io/discovery for https://analytics.adobe.io/api.
+// The request is sent like the requests of a client of the config, i.e. through its middlewares and logger
+// and within its rate limiter. MaxConcurrency only applies to the discovery request itself.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/discovery
+func Discover(config *Config) (*Discovery, error) {
+	client, err := newDiscoveryClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var data Discovery
+	_, err = doAPIRequest(client, http.MethodGet, "/me", map[string]string{}, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// newDiscoveryClient returns a client of the config for the discovery URL, which is not scoped to a company.
+func newDiscoveryClient(config *Config) (*Client, error) {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	discoveryURL, err := discoveryURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("missing ClientID")
+	}
+	tokenSource := newTokenSource(config)
+	if tokenSource == nil {
+		return nil, fmt.Errorf("missing AccessToken")
+	}
+
+	client := &Client{
+		httpClient: httpClient,
+		baseURL:    discoveryURL,
+		auth: &auth{
+			imsClientID:    config.ClientID,
+			imsOrgID:       config.OrgID,
+			imsTokenSource: tokenSource,
+		},
+		throttle:    newThrottle(config),
+		middlewares: append([]Middleware{}, config.Middlewares...),
+	}
+	if config.Logger != nil {
+		client.logger = loggerMiddleware(config.Logger)
+	}
+	return client, nil
+}
+
+// Companies returns the global companies of all IMS organizations.
+func (d *Discovery) Companies() []DiscoveryCompany {
+	var companies []DiscoveryCompany
+	if d.IMSOrgs == nil {
+		return companies
+	}
+	for _, org := range *d.IMSOrgs {
+		if org.Companies != nil {
+			companies = append(companies, *org.Companies...)
+		}
+	}
+	return companies
+}
+
+// NewCompanyClients discovers the global companies accessible with the config and returns a client per
+// global company ID. All clients share the HTTP client and credentials of the config, the CompanyID and
+// OrgID of the config are replaced by the discovered ones.
+func NewCompanyClients(config *Config) (map[string]*Client, error) {
+	discovery, err := Discover(config)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := map[string]*Client{}
+	if discovery.IMSOrgs == nil {
+		return clients, nil
+	}
+	for _, org := range *discovery.IMSOrgs {
+		if org.Companies == nil {
+			continue
+		}
+		for _, company := range *org.Companies {
+			companyConfig := *config
+			companyConfig.CompanyID = company.GlobalCompanyID
+			if org.IMSOrgID != "" {
+				companyConfig.OrgID = org.IMSOrgID
+			}
+
+			client, err := NewClient(&companyConfig)
+			if err != nil {
+				return nil, err
+			}
+			clients[company.GlobalCompanyID] = client
+		}
+	}
+	return clients, nil
+}
+
+// discoveryURL returns the configured discovery URL or derives it from the base URL.
+func discoveryURL(config *Config) (*url.URL, error) {
+	if config.DiscoveryURL != "" {
+		return verifyBaseURL(config.DiscoveryURL)
+	}
+
+	parsedBaseURL, err := verifyBaseURL(config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return parsedBaseURL.ResolveReference(&url.URL{Path: "/discovery"}), nil
+}
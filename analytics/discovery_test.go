@@ -0,0 +1,192 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestDiscover(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := "/discovery/me"
+
+	raw, err := ioutil.ReadFile("./testdata/Discovery.Me.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		if got := r.Header.Get("Authorization"); got != "Bearer imsAuthToken" {
+			t.Errorf("Authorization header: %s, want %s", got, "Bearer imsAuthToken")
+		}
+		if got := r.Header.Get("x-api-key"); got != "imsClientId" {
+			t.Errorf("x-api-key header: %s, want %s", got, "imsClientId")
+		}
+		fmt.Fprint(w, string(raw))
+	})
+
+	config := *testConfig
+	config.CompanyID = ""
+
+	discovery, err := analytics.Discover(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	companies := discovery.Companies()
+	if len(companies) != 3 {
+		t.Fatalf("Expected %d companies but got %d", 3, len(companies))
+	}
+
+	if companies[2].GlobalCompanyID != "agency0" || companies[2].CompanyName != "Agency Client" {
+		t.Errorf("Unexpected company %+v", companies[2])
+	}
+}
+
+func TestDiscoverDiscoveryURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/custom/discovery/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"imsUserId": "A1B2C3D4E5F6@techacct.adobe.com"}`)
+	})
+
+	config := *testConfig
+	config.DiscoveryURL = testServer.URL + "/custom/discovery/"
+
+	discovery, err := analytics.Discover(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if len(discovery.Companies()) != 0 {
+		t.Errorf("Expected no companies but got %d", len(discovery.Companies()))
+	}
+}
+
+func TestDiscoverMiddlewares(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/discovery/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Custom"); got != "value" {
+			t.Errorf("X-Custom header: %s, want %s", got, "value")
+		}
+		fmt.Fprint(w, `{"imsUserId": "A1B2C3D4E5F6@techacct.adobe.com"}`)
+	})
+
+	var paths []string
+	logger := &testLogger{}
+	config := *testConfig
+	config.CompanyID = ""
+	config.Logger = logger
+	config.RateLimiter = analytics.NewRateLimiter(100, 1)
+	config.Middlewares = []analytics.Middleware{
+		analytics.HeaderMiddleware(http.Header{"X-Custom": {"value"}}),
+		func(next analytics.Handler) analytics.Handler {
+			return func(req *analytics.Request) (*http.Response, error) {
+				paths = append(paths, req.Path)
+				return next(req)
+			}
+		},
+	}
+
+	_, err := analytics.Discover(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/me" {
+		t.Errorf("Expected middleware call for %s but got %v", "/me", paths)
+	}
+	if len(logger.messages) != 2 {
+		t.Errorf("Expected %d log messages but got %v", 2, logger.messages)
+	}
+}
+
+func TestDiscoverError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/discovery/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := analytics.Discover(testConfig)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestDiscoverInvalidConfig(t *testing.T) {
+	configs := []*analytics.Config{
+		{BaseURL: "domain.com/api", ClientID: "imsClientId", AccessToken: "imsAuthToken"},
+		{BaseURL: "https://domain.com/api", AccessToken: "imsAuthToken"},
+		{BaseURL: "https://domain.com/api", ClientID: "imsClientId"},
+	}
+
+	for _, config := range configs {
+		_, err := analytics.Discover(config)
+		if err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	}
+}
+
+func TestNewCompanyClients(t *testing.T) {
+	setup()
+	defer teardown()
+
+	raw, err := ioutil.ReadFile("./testdata/Discovery.Me.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc("/discovery/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, string(raw))
+	})
+	testMux.HandleFunc("/api/agency0/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-proxy-global-company-id"); got != "agency0" {
+			t.Errorf("x-proxy-global-company-id header: %s, want %s", got, "agency0")
+		}
+		if got := r.Header.Get("x-gw-ims-org-id"); got != "7A8B9C0D1E2F@AdobeOrg" {
+			t.Errorf("x-gw-ims-org-id header: %s, want %s", got, "7A8B9C0D1E2F@AdobeOrg")
+		}
+		fmt.Fprint(w, `{"companyId": 1, "login": "agency"}`)
+	})
+
+	clients, err := analytics.NewCompanyClients(testConfig)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if len(clients) != 3 {
+		t.Fatalf("Expected %d clients but got %d", 3, len(clients))
+	}
+
+	user, err := clients["agency0"].Users.GetCurrent()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if user.Login != "agency" {
+		t.Errorf("Expected user with Login=agency but got Login=%s", user.Login)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiskCache is a Cache which stores each entry as a file in a directory.
+// Entries survive restarts and can be shared by processes using the same directory.
+// The files are stored in a directory per segment of the key path, the part before "?", so deleting
+// a prefix ending with "?" or "/" only touches the entries of the matching directories.
+type DiskCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// diskEntry represents a DiskCache entry
+type diskEntry struct {
+	Key     string    `json:"key"`
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewDiskCache returns a new DiskCache storing entries in dir, the directory is created if necessary.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get returns the cached value of the key, if present and not expired.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file := c.file(key)
+	entry, err := readDiskEntry(file)
+	if err != nil || entry.Key != key {
+		return nil, false
+	}
+	if time.Now().After(entry.Expires) {
+		os.Remove(file)
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// Set stores the value of the key for the duration of ttl.
+// Write errors are ignored, the value is just not cached.
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(&diskEntry{Key: key, Value: value, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return
+	}
+
+	// write to a temporary file first, so readers never see partial entries
+	tmp, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	closeErr := tmp.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	file := c.file(key)
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// DeletePrefix deletes all values with keys starting with prefix.
+func (c *DiskCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// keys with a query prefix are stored in the directory of their path
+	if i := strings.Index(prefix, "?"); i >= 0 {
+		c.deleteFiles(c.pathDir(prefix[:i]), prefix, false)
+		return
+	}
+
+	i := strings.LastIndex(prefix, "/")
+	if i < 0 {
+		c.deleteFiles(c.dir, prefix, true)
+		return
+	}
+	dir := c.pathDir(prefix[:i])
+	if i < len(prefix)-1 {
+		// the last segment is partial, only the keys of the parent directory are candidates
+		c.deleteFiles(dir, prefix, true)
+		return
+	}
+
+	// keys below the path are stored in the subdirectories of its directory
+	subdirs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, subdir := range subdirs {
+		if subdir.IsDir() {
+			os.RemoveAll(filepath.Join(dir, subdir.Name()))
+		}
+	}
+}
+
+// deleteFiles deletes the entries of a directory with keys starting with prefix, or which can't be read.
+// Subdirectories are only searched if recursive is set.
+func (c *DiskCache) deleteFiles(dir, prefix string, recursive bool) {
+	filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if file != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(file) != ".json" {
+			return nil
+		}
+		entry, err := readDiskEntry(file)
+		if err != nil || strings.HasPrefix(entry.Key, prefix) {
+			os.Remove(file)
+		}
+		return nil
+	})
+}
+
+// pathDir returns the directory of the entries of a key path, it has a subdirectory per path segment.
+func (c *DiskCache) pathDir(path string) string {
+	segments := strings.Split(path, "/")
+	elems := make([]string, 0, len(segments)+1)
+	elems = append(elems, c.dir)
+	for _, segment := range segments {
+		sum := sha256.Sum256([]byte(segment))
+		elems = append(elems, hex.EncodeToString(sum[:16]))
+	}
+	return filepath.Join(elems...)
+}
+
+// file returns the file name of a key.
+func (c *DiskCache) file(key string) string {
+	path := key
+	if i := strings.Index(key, "?"); i >= 0 {
+		path = key[:i]
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.pathDir(path), hex.EncodeToString(sum[:])+".json")
+}
+
+// readDiskEntry reads a cache entry from a file.
+func readDiskEntry(file string) (*diskEntry, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry diskEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
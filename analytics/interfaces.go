@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// Service interfaces, e.g. to substitute services with mocks in tests.
+// The package analyticsmock provides generated mocks.
+
+//go:generate go run ../analyticsmock/gen.go -source interfaces.go -output ../analyticsmock/mocks.go
+
+// AnnotationsAPI is the interface implemented by AnnotationsService.
+type AnnotationsAPI interface {
+	GetAll(rsids, locale, filterByIDs string, filterByDateRange *DateInterval,
+		limit, page int64, sortDirection, sortProperty string,
+		expansion, includeType []string) (*Annotations, error)
+	GetByID(id, locale string, expansion []string) (*Annotation, error)
+	Create(annotation *Annotation) (*Annotation, error)
+	Update(id string, annotation *Annotation) (*Annotation, error)
+	Delete(id string) error
+}
+
+// CalculatedMetricsAPI is the interface implemented by CalculatedMetricsService.
+type CalculatedMetricsAPI interface {
+	GetAll(rsids, ownerID, filterByIds, toBeUsedInRsid, locale, name, tagNames string,
+		favorite, approved bool,
+		limit, page int64,
+		sortDirection, sortProperty string,
+		expansion, includeType []string) (*CalculatedMetrics, error)
+	GetByID(id, locale string, expansion []string) (*CalculatedMetric, error)
+}
+
+// CollectionsAPI is the interface implemented by CollectionsService.
+type CollectionsAPI interface {
+	GetAll(rsids, rsidContains string, limit, page int64, expansion []string) (*Collections, error)
+	GetByID(id string, expansion []string) (*Collection, error)
+}
+
+// DateRangesAPI is the interface implemented by DateRangesService.
+type DateRangesAPI interface {
+	GetAll(locale, filterByIDs string, limit, page int64, expansion, includeType []string) (*DateRanges, error)
+	GetByID(id, locale string, expansion []string) (*DateRange, error)
+	Create(dateRange *DateRange) (*DateRange, error)
+	Update(id string, dateRange *DateRange) (*DateRange, error)
+	Delete(id string) error
+}
+
+// DimensionsAPI is the interface implemented by DimensionsService.
+type DimensionsAPI interface {
+	GetAll(rsID, locale string, segmentable, reportable, classifiable bool, expansion []string) (*[]Dimension, error)
+	GetByID(rsID, id, locale string, expansion []string) (*Dimension, error)
+}
+
+// MetricsAPI is the interface implemented by MetricsService.
+type MetricsAPI interface {
+	GetAll(rsID, locale string, segmentable bool, expansion []string) (*[]Metric, error)
+	GetByID(rsID, id, locale string, expansion []string) (*Metric, error)
+}
+
+// ProjectsAPI is the interface implemented by ProjectsService.
+type ProjectsAPI interface {
+	GetAll(locale, filterByIDs string, limit, page int64, expansion, includeType []string) (*Projects, error)
+	GetByID(id, locale string, expansion []string) (*Project, error)
+	Create(project *Project) (*Project, error)
+	Update(id string, project *Project) (*Project, error)
+	Delete(id string) error
+}
+
+// ReportsAPI is the interface implemented by ReportsService.
+// NewItemResolver is not part of the interface, as the ItemResolver requires a ReportsService.
+type ReportsAPI interface {
+	Run(rankedRequest *RankedRequest) (*RankedReportData, error)
+	TopItems(rsid, dimension, search, dateRange string, limit, page int64) (*TopItemsData, error)
+	RunRealtime(realtimeRequest *RealtimeRequest) (*RealtimeReportData, error)
+	PollRealtime(ctx context.Context, realtimeRequest *RealtimeRequest, interval time.Duration) (<-chan RealtimeBucket, <-chan error)
+	RunFreeformTable(table *FreeformTable) (*FreeformTableData, error)
+	RunComparison(rankedRequest *RankedRequest, current, previous string) (*ComparisonReport, error)
+	RunPercentChange(rankedRequest *RankedRequest) (*ComparisonReport, error)
+}
+
+// SegmentsAPI is the interface implemented by SegmentsService.
+type SegmentsAPI interface {
+	GetAll(rsids, segmentFilter, locale, name, tagNames, filterByPublishedSegments string,
+		limit, page int64, sortDirection, sortProperty string,
+		expansion []string, includeType []string) (*Segments, error)
+	GetByID(id, locale string, expansion []string) (*Segment, error)
+}
+
+// SharesAPI is the interface implemented by SharesService.
+type SharesAPI interface {
+	GetAll(limit, page int64) (*Shares, error)
+	GetByID(id int) (*Share, error)
+	GetByComponentIDs(componentType string, componentIDs []string) (*[]ComponentShares, error)
+	Share(shareRequest *ShareRequest) (*Share, error)
+	ShareWithUser(componentType, componentID string, user *User) (*Share, error)
+	ShareWithOwner(componentType, componentID string, owner *Owner) (*Share, error)
+	ShareWithGroup(componentType, componentID string, groupID int) (*Share, error)
+	ShareWithAll(componentType, componentID string) (*Share, error)
+	Update(components *[]ComponentShares) (*[]ComponentShares, error)
+	Delete(id int) error
+}
+
+// TagsAPI is the interface implemented by TagsService.
+type TagsAPI interface {
+	GetAll(limit, page int64) (*Tags, error)
+	GetByID(id string) (*Tag, error)
+	Create(tags *[]Tag) (*[]Tag, error)
+	Delete(id string) error
+	GetByComponentIDs(componentType string, componentIDs []string) (*[]TaggedComponent, error)
+	GetComponentsByTagNames(componentType string, tagNames []string) (*[]TaggedComponent, error)
+	Tag(components *[]TaggedComponent) (*[]TaggedComponent, error)
+	Untag(componentType string, componentIDs []string) error
+}
+
+// UsersAPI is the interface implemented by UsersService.
+type UsersAPI interface {
+	GetAll(limit, page int64) (*Users, error)
+	GetCurrent() (*User, error)
+}
+
+// verify the services implement the interfaces
+var (
+	_ AnnotationsAPI       = (*AnnotationsService)(nil)
+	_ CalculatedMetricsAPI = (*CalculatedMetricsService)(nil)
+	_ CollectionsAPI       = (*CollectionsService)(nil)
+	_ DateRangesAPI        = (*DateRangesService)(nil)
+	_ DimensionsAPI        = (*DimensionsService)(nil)
+	_ MetricsAPI           = (*MetricsService)(nil)
+	_ ProjectsAPI          = (*ProjectsService)(nil)
+	_ ReportsAPI           = (*ReportsService)(nil)
+	_ SegmentsAPI          = (*SegmentsService)(nil)
+	_ SharesAPI            = (*SharesService)(nil)
+	_ TagsAPI              = (*TagsService)(nil)
+	_ UsersAPI             = (*UsersService)(nil)
+)
@@ -0,0 +1,324 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger logs structured messages with alternating keys and values.
+// It is implemented by *slog.Logger, NewStdLogger adapts a *log.Logger.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// DebugEnabler is optionally implemented by a Logger to report whether debug messages are logged.
+// If DebugEnabled returns false, requests and responses are not logged and their bodies are not captured.
+type DebugEnabler interface {
+	DebugEnabled() bool
+}
+
+// redacted replaces secret values in logs
+const redacted = "[REDACTED]"
+
+// maxLoggedBody is the maximum number of logged body bytes
+const maxLoggedBody = 4096
+
+// maxCapturedBody is the maximum number of captured response body bytes, longer bodies are only logged with their length
+const maxCapturedBody = 1 << 20
+
+// secretNames are parts of header, param and JSON field names holding secrets
+var secretNames = []string{"authorization", "api-key", "apikey", "secret", "token", "password", "cookie"}
+
+// stdLogger is a Logger writing key=value pairs to a *log.Logger
+type stdLogger struct {
+	logger *log.Logger
+	debug  bool
+}
+
+// NewStdLogger returns a Logger writing to logger, debug messages are only written if debug is true.
+// The standard logger is used if logger is nil.
+func NewStdLogger(logger *log.Logger, debug bool) Logger {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return &stdLogger{logger: logger, debug: debug}
+}
+
+// Debug logs a message at debug level.
+func (l *stdLogger) Debug(msg string, keysAndValues ...interface{}) {
+	if l.debug {
+		l.log("DEBUG", msg, keysAndValues)
+	}
+}
+
+// DebugEnabled returns true if debug messages are logged.
+func (l *stdLogger) DebugEnabled() bool {
+	return l.debug
+}
+
+// Error logs a message at error level.
+func (l *stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log("ERROR", msg, keysAndValues)
+}
+
+// log writes a message with level and key=value pairs.
+func (l *stdLogger) log(level, msg string, keysAndValues []interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", level, msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = "!MISSING"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%q", keysAndValues[i], fmt.Sprint(value))
+	}
+	l.logger.Print(b.String())
+}
+
+// loggerMiddleware returns a Middleware which logs requests and responses at debug level and failed requests at error level.
+// Secrets in headers, params and bodies are redacted when the values are formatted, i.e. only if a message is logged.
+// The response is logged once its body is closed, the body is captured while the caller reads it.
+func loggerMiddleware(logger Logger) Middleware {
+	debug := true
+	if enabler, ok := logger.(DebugEnabler); ok {
+		debug = enabler.DebugEnabled()
+	}
+
+	return func(next Handler) Handler {
+		return func(req *Request) (*http.Response, error) {
+			if debug {
+				logger.Debug("analytics request",
+					"method", req.Method,
+					"path", req.Path,
+					"params", redactedParams(req.Params),
+					"header", redactedHeader(req.Header),
+					"body", &redactedBody{data: req.Body, n: len(req.Body)})
+			}
+
+			start := time.Now()
+			res, err := next(req)
+			duration := time.Since(start)
+			if err != nil {
+				logger.Error("analytics request failed",
+					"method", req.Method,
+					"path", req.Path,
+					"duration", duration,
+					"error", err)
+				return res, err
+			}
+
+			logError := func() {
+				if res.StatusCode >= 400 {
+					logger.Error("analytics request failed",
+						"method", req.Method,
+						"path", req.Path,
+						"status", res.StatusCode,
+						"duration", duration)
+				}
+			}
+			if !debug {
+				logError()
+				return res, nil
+			}
+
+			header := res.Header
+			status := res.StatusCode
+			res.Body = &loggingBody{
+				ReadCloser: res.Body,
+				log: func(body *redactedBody) {
+					logger.Debug("analytics response",
+						"method", req.Method,
+						"path", req.Path,
+						"status", status,
+						"duration", duration,
+						"header", redactedHeader(header),
+						"body", body)
+					logError()
+				},
+			}
+			return res, nil
+		}
+	}
+}
+
+// loggingBody is a response body which captures the read bytes and logs the response when closed
+type loggingBody struct {
+	io.ReadCloser
+	body redactedBody
+	once sync.Once
+	log  func(body *redactedBody)
+}
+
+// Read reads from the body and captures up to maxCapturedBody bytes.
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if capture := maxCapturedBody - len(b.body.data); capture > 0 {
+		if capture > n {
+			capture = n
+		}
+		b.body.data = append(b.body.data, p[:capture]...)
+	}
+	b.body.n += n
+	return n, err
+}
+
+// Close closes the body and logs the response.
+func (b *loggingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.log(&b.body) })
+	return err
+}
+
+// redactedHeader formats a header with secret values redacted
+type redactedHeader http.Header
+
+// String returns the redacted header.
+func (h redactedHeader) String() string {
+	return redactHeader(http.Header(h))
+}
+
+// MarshalText formats the header for structured loggers like *slog.Logger.
+func (h redactedHeader) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// redactedParams formats params with secret values redacted
+type redactedParams map[string]string
+
+// String returns the redacted params.
+func (p redactedParams) String() string {
+	return redactParams(p)
+}
+
+// MarshalText formats the params for structured loggers like *slog.Logger.
+func (p redactedParams) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// redactedBody formats a body of n bytes with secret JSON fields redacted, data holds the captured bytes
+type redactedBody struct {
+	data []byte
+	n    int
+}
+
+// String returns the redacted body, bodies which were not captured completely are only formatted with their length.
+func (b *redactedBody) String() string {
+	if len(b.data) < b.n {
+		return fmt.Sprintf("<%d bytes>", b.n)
+	}
+	return redactBody(b.data)
+}
+
+// MarshalText formats the body for structured loggers like *slog.Logger.
+func (b *redactedBody) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// isSecret returns true if the header, param or field name holds a secret.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns the header as string with secret values redacted, e.g. the bearer token.
+func redactHeader(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header[key], ",")
+		if isSecret(key) {
+			if strings.HasPrefix(value, "Bearer ") {
+				value = "Bearer " + redacted
+			} else {
+				value = redacted
+			}
+		}
+		parts = append(parts, key+": "+value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// redactParams returns the params as string with secret values redacted.
+func redactParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := params[key]
+		if isSecret(key) {
+			value = redacted
+		}
+		parts = append(parts, key+"="+value)
+	}
+	return strings.Join(parts, "&")
+}
+
+// redactBody returns the body as string with secret JSON fields redacted.
+// Bodies which are not JSON are only logged with their length, long bodies are truncated.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	out, _ := json.Marshal(redactJSON(data))
+	if len(out) > maxLoggedBody {
+		return string(out[:maxLoggedBody]) + "..."
+	}
+	return string(out)
+}
+
+// redactJSON redacts secret fields of JSON data recursively.
+func redactJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecret(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactJSON(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+	return data
+}
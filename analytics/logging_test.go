@@ -0,0 +1,158 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+// testLogger records log messages
+type testLogger struct {
+	messages []string
+	noDebug  bool
+}
+
+func (l *testLogger) DebugEnabled() bool {
+	return !l.noDebug
+}
+
+func (l *testLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(append([]interface{}{"DEBUG ", msg}, keysAndValues...)...))
+}
+
+func (l *testLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(append([]interface{}{"ERROR ", msg}, keysAndValues...)...))
+}
+
+func TestClientLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprint(w, `{"content": [{"login": "jdoe", "accessToken": "responseSecret"}]}`)
+	})
+
+	logger := &testLogger{}
+	config := *testConfig
+	config.Logger = logger
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	users, err := client.Users.GetAll(10, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(*users.Content) != 1 {
+		t.Errorf("Expected %d users but got %d", 1, len(*users.Content))
+	}
+
+	if len(logger.messages) != 2 {
+		t.Fatalf("Expected %d log messages but got %d", 2, len(logger.messages))
+	}
+	output := strings.Join(logger.messages, "\n")
+	for _, secret := range []string{"imsAuthToken", "imsClientId", "responseSecret", "session=secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be redacted in %s", secret, output)
+		}
+	}
+	for _, want := range []string{"Authorization: Bearer [REDACTED]", "X-Api-Key: [REDACTED]", `"login":"jdoe"`, "limit=10"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in %s", want, output)
+		}
+	}
+}
+
+func TestClientLoggerError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	logger := &testLogger{}
+	config := *testConfig
+	config.Logger = logger
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	_, err = client.Users.GetCurrent()
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+	if len(logger.messages) != 3 || !strings.HasPrefix(logger.messages[2], "ERROR analytics request failed") {
+		t.Errorf("Expected error log message but got %v", logger.messages)
+	}
+}
+
+func TestClientLoggerDebugDisabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"content": [{"login": "jdoe"}]}`)
+	})
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	logger := &testLogger{noDebug: true}
+	config := *testConfig
+	config.Logger = logger
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	users, err := client.Users.GetAll(10, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(*users.Content) != 1 {
+		t.Errorf("Expected %d users but got %d", 1, len(*users.Content))
+	}
+	if len(logger.messages) != 0 {
+		t.Errorf("Expected no log messages but got %v", logger.messages)
+	}
+
+	_, err = client.Users.GetCurrent()
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+	if len(logger.messages) != 1 || !strings.HasPrefix(logger.messages[0], "ERROR analytics request failed") {
+		t.Errorf("Expected error log message but got %v", logger.messages)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := analytics.NewStdLogger(log.New(&buf, "", 0), false)
+
+	logger.Debug("hidden", "key", "value")
+	logger.Error("failed", "status", 500, "path", "/users")
+
+	if buf.String() != "ERROR failed status=\"500\" path=\"/users\"\n" {
+		t.Errorf("Unexpected log output %q", buf.String())
+	}
+}
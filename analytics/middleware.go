@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Request represents an API request passed through the middlewares of a client.
+// Path is relative to the company, e.g. "/reports", Header holds the headers of the HTTP request.
+// Context is the context of the HTTP request, see Client.WithContext.
+type Request struct {
+	Context context.Context
+	Method  string
+	Path    string
+	Params  map[string]string
+	Body    []byte
+	Header  http.Header
+}
+
+// Handler sends an API request and returns the HTTP response.
+type Handler func(req *Request) (*http.Response, error)
+
+// Middleware wraps a Handler, e.g. to modify the request or to inspect the response.
+// Middlewares must not consume the response body unless they replace it.
+type Middleware func(next Handler) Handler
+
+// chainMiddlewares wraps handler with the middlewares, the first middleware sees the request first.
+func chainMiddlewares(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// LoggingMiddleware returns a Middleware which logs method, path, status and duration of each request.
+// The standard logger is used if logger is nil.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return func(next Handler) Handler {
+		return func(req *Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next(req)
+			duration := time.Since(start).Round(time.Millisecond)
+			if err != nil && res == nil {
+				logger.Printf("%s %s failed after %s: %v", req.Method, req.Path, duration, err)
+				return res, err
+			}
+			logger.Printf("%s %s %d (%s)", req.Method, req.Path, res.StatusCode, duration)
+			return res, err
+		}
+	}
+}
+
+// HeaderMiddleware returns a Middleware which sets the headers on each request.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*http.Response, error) {
+			for key, values := range header {
+				req.Header.Del(key)
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestClientMiddlewares(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/dateranges/57a9ad685fe707f55ffb68f5", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		if got := r.Header.Get("X-Custom"); got != "inner" {
+			t.Errorf("Expected header X-Custom %q but got %q", "inner", got)
+		}
+		fmt.Fprint(w, `{"id": "57a9ad685fe707f55ffb68f5"}`)
+	})
+
+	var calls []string
+	recording := func(name string) analytics.Middleware {
+		return func(next analytics.Handler) analytics.Handler {
+			return func(req *analytics.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+req.Method+" "+req.Path)
+				req.Header.Set("X-Custom", name)
+				res, err := next(req)
+				if err == nil {
+					calls = append(calls, fmt.Sprintf("%s %d", name, res.StatusCode))
+				}
+				return res, err
+			}
+		}
+	}
+
+	var body []byte
+	config := *testConfig
+	config.Middlewares = []analytics.Middleware{recording("outer")}
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	client.Use(recording("inner"), func(next analytics.Handler) analytics.Handler {
+		return func(req *analytics.Request) (*http.Response, error) {
+			body = req.Body
+			return next(req)
+		}
+	})
+
+	_, err = client.DateRanges.Update("57a9ad685fe707f55ffb68f5", &analytics.DateRange{Name: "Last week"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := []string{
+		"outer PUT /dateranges/57a9ad685fe707f55ffb68f5",
+		"inner PUT /dateranges/57a9ad685fe707f55ffb68f5",
+		"inner 200",
+		"outer 200",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected middleware calls %v but got %v", expected, calls)
+	}
+	if !strings.Contains(string(body), `"name":"Last week"`) {
+		t.Errorf("Expected request body with name but got %s", body)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var buf bytes.Buffer
+	config := *testConfig
+	config.Middlewares = []analytics.Middleware{analytics.LoggingMiddleware(log.New(&buf, "", 0))}
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	client.Users.GetCurrent()
+
+	if !strings.HasPrefix(buf.String(), "GET /users/me 500 (") {
+		t.Errorf("Unexpected log output %q", buf.String())
+	}
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Request-Id"); got != "1234" {
+			t.Errorf("Expected header X-Request-Id %q but got %q", "1234", got)
+		}
+		if got := r.Header.Get("x-api-key"); got != "imsClientId" {
+			t.Errorf("Expected header x-api-key %q but got %q", "imsClientId", got)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	config := *testConfig
+	config.Middlewares = []analytics.Middleware{analytics.HeaderMiddleware(http.Header{"X-Request-Id": {"1234"}})}
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	_, err = client.Users.GetCurrent()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ClientPool manages clients for multiple global companies.
+// All clients share the HTTP client and credentials of the pool config, each company has its own rate limit.
+// It is safe for concurrent use.
+type ClientPool struct {
+	config     Config
+	httpClient *http.Client
+	rate       float64
+	burst      int
+
+	mu       sync.Mutex
+	orgIDs   map[string]string
+	limiters map[string]*RateLimiter
+	clients  map[string]*Client
+}
+
+// CompanyResult represents the result of a call for a company
+type CompanyResult struct {
+	CompanyID string
+	Value     interface{}
+	Err       error
+}
+
+// NewClientPool returns a new ClientPool for the passed config, the CompanyID of the config is ignored.
+// Each company is limited to rate requests per second with bursts of burst requests,
+// a rate of 0 disables rate limiting. The rate limiter of each company replaces the RateLimiter of the config,
+// MaxConcurrency applies to each company separately.
+func NewClientPool(config *Config, rate float64, burst int) (*ClientPool, error) {
+	if _, err := verifyBaseURL(config.BaseURL); err != nil {
+		return nil, err
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &ClientPool{
+		config:     *config,
+		httpClient: httpClient,
+		rate:       rate,
+		burst:      burst,
+		orgIDs:     map[string]string{},
+		limiters:   map[string]*RateLimiter{},
+		clients:    map[string]*Client{},
+	}, nil
+}
+
+// Discover adds all global companies accessible with the pool config to the pool.
+func (p *ClientPool) Discover() error {
+	discovery, err := Discover(&p.config)
+	if err != nil {
+		return err
+	}
+	if discovery.IMSOrgs == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, org := range *discovery.IMSOrgs {
+		if org.Companies == nil {
+			continue
+		}
+		for _, company := range *org.Companies {
+			if _, ok := p.orgIDs[company.GlobalCompanyID]; !ok {
+				p.orgIDs[company.GlobalCompanyID] = org.IMSOrgID
+			}
+		}
+	}
+	return nil
+}
+
+// Add adds a global company to the pool. An empty orgID defaults to the OrgID of the pool config.
+func (p *ClientPool) Add(companyID, orgID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.orgIDs[companyID]; !ok {
+		p.orgIDs[companyID] = orgID
+	}
+}
+
+// CompanyIDs returns the sorted global company IDs of the pool.
+func (p *ClientPool) CompanyIDs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	companyIDs := make([]string, 0, len(p.orgIDs))
+	for companyID := range p.orgIDs {
+		companyIDs = append(companyIDs, companyID)
+	}
+	sort.Strings(companyIDs)
+	return companyIDs
+}
+
+// SetRateLimit sets the rate limit of a global company.
+func (p *ClientPool) SetRateLimit(companyID string, rate float64, burst int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.limiter(companyID).SetLimit(rate, burst)
+}
+
+// Client returns the client of a global company, the company is added to the pool if necessary.
+func (p *ClientPool) Client(companyID string) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[companyID]; ok {
+		return client, nil
+	}
+
+	config := p.config
+	config.CompanyID = companyID
+	if orgID := p.orgIDs[companyID]; orgID != "" {
+		config.OrgID = orgID
+	}
+
+	config.HTTPClient = p.httpClient
+	config.RateLimiter = p.limiter(companyID)
+
+	client, err := NewClient(&config)
+	if err != nil {
+		return nil, fmt.Errorf("company %s: %v", companyID, err)
+	}
+
+	if _, ok := p.orgIDs[companyID]; !ok {
+		p.orgIDs[companyID] = ""
+	}
+	p.clients[companyID] = client
+	return client, nil
+}
+
+// limiter returns the rate limiter of a global company, the caller must hold the lock.
+func (p *ClientPool) limiter(companyID string) *RateLimiter {
+	limiter, ok := p.limiters[companyID]
+	if !ok {
+		limiter = NewRateLimiter(p.rate, p.burst)
+		p.limiters[companyID] = limiter
+	}
+	return limiter
+}
+
+// Do calls fn with the client of each passed global company, running up to concurrency calls at once.
+// The results are ordered like the passed company IDs. A concurrency below 1 runs all calls at once.
+func (p *ClientPool) Do(companyIDs []string, concurrency int, fn func(client *Client) (interface{}, error)) []CompanyResult {
+	if concurrency < 1 {
+		concurrency = len(companyIDs)
+	}
+
+	results := make([]CompanyResult, len(companyIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, companyID := range companyIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, companyID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i].CompanyID = companyID
+			client, err := p.Client(companyID)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Value, results[i].Err = fn(client)
+		}(i, companyID)
+	}
+	wg.Wait()
+	return results
+}
+
+// DoAll calls fn with the client of each global company of the pool, see Do.
+func (p *ClientPool) DoAll(concurrency int, fn func(client *Client) (interface{}, error)) []CompanyResult {
+	return p.Do(p.CompanyIDs(), concurrency, fn)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestClientPoolDo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, companyID := range []string{"exampl0", "exampl1"} {
+		companyID := companyID
+		testMux.HandleFunc("/api/"+companyID+"/users/me", func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("x-proxy-global-company-id"); got != companyID {
+				t.Errorf("x-proxy-global-company-id header: %s, want %s", got, companyID)
+			}
+			fmt.Fprintf(w, `{"login": "%s"}`, companyID)
+		})
+	}
+	testMux.HandleFunc("/api/agency0/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	pool, err := analytics.NewClientPool(testConfig, 0, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	results := pool.Do([]string{"exampl1", "agency0", "exampl0"}, 2, func(client *analytics.Client) (interface{}, error) {
+		return client.Users.GetCurrent()
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("Expected %d results but got %d", 3, len(results))
+	}
+	for i, companyID := range []string{"exampl1", "agency0", "exampl0"} {
+		if results[i].CompanyID != companyID {
+			t.Errorf("Expected result of company %s but got %s", companyID, results[i].CompanyID)
+		}
+	}
+	if results[0].Err != nil || results[0].Value.(*analytics.User).Login != "exampl1" {
+		t.Errorf("Unexpected result %+v", results[0])
+	}
+	if results[1].Err == nil {
+		t.Errorf("Expected error but got none")
+	}
+
+	if got := pool.CompanyIDs(); fmt.Sprint(got) != "[agency0 exampl0 exampl1]" {
+		t.Errorf("Company IDs: %v, want %v", got, "[agency0 exampl0 exampl1]")
+	}
+}
+
+func TestClientPoolDiscover(t *testing.T) {
+	setup()
+	defer teardown()
+
+	raw, err := ioutil.ReadFile("./testdata/Discovery.Me.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc("/discovery/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, string(raw))
+	})
+	testMux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"login": "%s"}`, r.Header.Get("x-gw-ims-org-id"))
+	})
+
+	pool, err := analytics.NewClientPool(testConfig, 0, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	err = pool.Discover()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	results := pool.DoAll(0, func(client *analytics.Client) (interface{}, error) {
+		return client.Users.GetCurrent()
+	})
+
+	want := map[string]string{
+		"agency0": "7A8B9C0D1E2F@AdobeOrg",
+		"exampl0": "1A2B3C4D5E6F@AdobeOrg",
+		"exampl1": "1A2B3C4D5E6F@AdobeOrg",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results but got %d", len(want), len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Error: %v", result.Err)
+			continue
+		}
+		if got := result.Value.(*analytics.User).Login; got != want[result.CompanyID] {
+			t.Errorf("Org of company %s: %s, want %s", result.CompanyID, got, want[result.CompanyID])
+		}
+	}
+}
+
+type countingTokenSource struct {
+	calls int32
+}
+
+func (s *countingTokenSource) Token() (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return "sharedToken", nil
+}
+
+func TestClientPoolSharedTokenSource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer sharedToken" {
+			t.Errorf("Authorization header: %s, want %s", got, "Bearer sharedToken")
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	tokenSource := &countingTokenSource{}
+	config := *testConfig
+	config.AccessToken = ""
+	config.TokenSource = tokenSource
+
+	pool, err := analytics.NewClientPool(&config, 0, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	pool.Do([]string{"exampl0", "exampl1"}, 0, func(client *analytics.Client) (interface{}, error) {
+		return client.Users.GetCurrent()
+	})
+
+	if calls := atomic.LoadInt32(&tokenSource.calls); calls != 2 {
+		t.Errorf("Expected %d token requests but got %d", 2, calls)
+	}
+}
+
+func TestClientPoolRateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	pool, err := analytics.NewClientPool(testConfig, 0, 0)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	pool.SetRateLimit("exampl0", 20, 1)
+
+	getCurrent := func(client *analytics.Client) (interface{}, error) {
+		return client.Users.GetCurrent()
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		pool.Do([]string{"exampl1"}, 1, getCurrent)
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("Expected unlimited company but requests took %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		pool.Do([]string{"exampl0"}, 1, getCurrent)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected rate limited requests but requests took %v", elapsed)
+	}
+}
+
+func TestNewClientPoolInvalidURL(t *testing.T) {
+	_, err := analytics.NewClientPool(&analytics.Config{BaseURL: "domain.com/api"}, 0, 0)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProjectsService handles Analysis Workspace projects.
+// Analytics docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects
+type ProjectsService struct {
+	client *Client
+}
+
+// GetAll returns a page of projects for the user.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects/findProjects
+func (s *ProjectsService) GetAll(locale, filterByIDs string, limit, page int64, expansion, includeType []string) (*Projects, error) {
+	var params = map[string]string{}
+	if locale != "" {
+		params["locale"] = locale
+	}
+	if filterByIDs != "" {
+		params["filterByIds"] = filterByIDs
+	}
+	params["pagination"] = "true"
+	params["limit"] = strconv.FormatInt(limit, 10)
+	params["page"] = strconv.FormatInt(page, 10)
+	if len(expansion) > 0 {
+		params["expansion"] = strings.Join(expansion[:], ",")
+	}
+	if len(includeType) > 0 {
+		params["includeType"] = strings.Join(includeType[:], ",")
+	}
+
+	var data Projects
+	err := s.client.get("/projects", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetByID returns a single project.
+// Add "definition" to expansion to include the project definition.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects/getProject
+func (s *ProjectsService) GetByID(id, locale string, expansion []string) (*Project, error) {
+	var params = map[string]string{}
+	if locale != "" {
+		params["locale"] = locale
+	}
+	if len(expansion) > 0 {
+		params["expansion"] = strings.Join(expansion[:], ",")
+	}
+
+	var data Project
+	err := s.client.get(fmt.Sprintf("/projects/%s", id), params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Create creates a new project.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects/createProject
+func (s *ProjectsService) Create(project *Project) (*Project, error) {
+	reqJSON, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data Project
+	err = s.client.create("/projects", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Update updates an existing project.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects/updateProject
+func (s *ProjectsService) Update(id string, project *Project) (*Project, error) {
+	reqJSON, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data Project
+	err = s.client.put(fmt.Sprintf("/projects/%s", id), map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Delete deletes a project.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/projects/deleteProject
+func (s *ProjectsService) Delete(id string) error {
+	return s.client.delete(fmt.Sprintf("/projects/%s", id), map[string]string{}, nil, nil)
+}
+
+// Components returns the segments, metrics, calculated metrics, dimensions and date ranges
+// referenced by the project definition. IDs are unique and sorted by their position in the definition.
+// The project must have been retrieved with the "definition" expansion.
+func (p *Project) Components() (*ProjectComponents, error) {
+	components := &ProjectComponents{}
+	if len(p.Definition) == 0 {
+		return components, nil
+	}
+
+	// the definition is decoded token by token to keep the order of object keys
+	var refs []projectComponentRef
+	dec := json.NewDecoder(bytes.NewReader(p.Definition))
+	_, err := collectProjectComponents(dec, &refs)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		key := ref.componentType + "/" + ref.id
+		if ref.id == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch ref.componentType {
+		case "Segment":
+			components.Segments = append(components.Segments, ref.id)
+		case "Metric":
+			components.Metrics = append(components.Metrics, ref.id)
+		case "CalculatedMetric":
+			components.CalculatedMetrics = append(components.CalculatedMetrics, ref.id)
+		case "Dimension":
+			components.Dimensions = append(components.Dimensions, ref.id)
+		case "DateRange":
+			components.DateRanges = append(components.DateRanges, ref.id)
+		}
+	}
+	return components, nil
+}
+
+// projectComponentRef represents a component reference of a project definition
+type projectComponentRef struct {
+	id            string
+	componentType string
+}
+
+// collectProjectComponents decodes the next value of a project definition and collects all component references
+// in the order of the definition. Workspace marks component references as objects with "__entity__": true,
+// an "id" and a "type". A reference is ordered by the start of its object, before any nested references.
+// The value is returned if it is a scalar, objects and arrays return nil.
+func collectProjectComponents(dec *json.Decoder, refs *[]projectComponentRef) (json.Token, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('['):
+		for dec.More() {
+			if _, err := collectProjectComponents(dec, refs); err != nil {
+				return nil, err
+			}
+		}
+	case json.Delim('{'):
+		// reserve the position of the object in case it is a reference
+		index := len(*refs)
+		*refs = append(*refs, projectComponentRef{})
+		var entity bool
+		var ref projectComponentRef
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			// values of all keys are visited, references may be nested in any of them
+			value, err := collectProjectComponents(dec, refs)
+			if err != nil {
+				return nil, err
+			}
+			switch keyToken {
+			case "__entity__":
+				entity, _ = value.(bool)
+			case "id":
+				ref.id, _ = value.(string)
+			case "type":
+				ref.componentType, _ = value.(string)
+			}
+		}
+		if entity {
+			(*refs)[index] = ref
+		}
+	default:
+		return token, nil
+	}
+
+	// consume the closing delimiter
+	_, err = dec.Token()
+	return nil, err
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestProjectsGetAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/projects"
+
+	raw, err := ioutil.ReadFile("./testdata/Projects.GetAll.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"locale":      "en_US",
+			"filterByIds": "1,2",
+			"pagination":  "true",
+			"limit":       "10",
+			"page":        "0",
+			"expansion":   "a,b",
+			"includeType": "all",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	projects, err := testClient.Projects.GetAll("en_US", "1,2", 10, 0, []string{"a", "b"}, []string{"all"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*projects.Content) != 2 {
+		t.Errorf("Expected %d projects but got %d", 2, len(*projects.Content))
+		return
+	}
+}
+
+func TestProjectsGetAllError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Projects.GetAll("en_US", "1,2", 10, 0, []string{"a", "b"}, []string{"all"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestProjectsGetByID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/projects/5ab3e4e0e8a4a6a7c7d0f111"
+
+	raw, err := ioutil.ReadFile("./testdata/Projects.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"locale":    "en_US",
+			"expansion": "definition",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	project, err := testClient.Projects.GetByID("5ab3e4e0e8a4a6a7c7d0f111", "en_US", []string{"definition"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if project.ID != "5ab3e4e0e8a4a6a7c7d0f111" {
+		t.Errorf("Expected project with ID=5ab3e4e0e8a4a6a7c7d0f111 but got ID=%s", project.ID)
+	}
+
+	if len(project.Definition) == 0 {
+		t.Errorf("Expected project definition but got none")
+	}
+}
+
+func TestProjectsGetByIDError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/projects/5ab3e4e0e8a4a6a7c7d0f111", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := testClient.Projects.GetByID("5ab3e4e0e8a4a6a7c7d0f111", "en_US", []string{"definition"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestProjectsCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/projects"
+
+	raw, err := ioutil.ReadFile("./testdata/Projects.Create.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(`{"name": "Backup of Weekly KPIs", "rsid": "amc.aem.prod", "type": "project", "definition": {"version": "3"}}`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	project, err := testClient.Projects.Create(&analytics.Project{
+		Name:       "Backup of Weekly KPIs",
+		RSID:       "amc.aem.prod",
+		Type:       "project",
+		Definition: json.RawMessage(`{"version": "3"}`),
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if project.ID != "5ab3e4e0e8a4a6a7c7d0f113" {
+		t.Errorf("Expected project with ID=5ab3e4e0e8a4a6a7c7d0f113 but got ID=%s", project.ID)
+	}
+}
+
+func TestProjectsCreateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := testClient.Projects.Create(&analytics.Project{Name: "Backup of Weekly KPIs"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestProjectsUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/projects/5ab3e4e0e8a4a6a7c7d0f113"
+
+	raw, err := ioutil.ReadFile("./testdata/Projects.Create.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(`{"name": "Backup of Weekly KPIs"}`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	project, err := testClient.Projects.Update("5ab3e4e0e8a4a6a7c7d0f113", &analytics.Project{Name: "Backup of Weekly KPIs"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if project.Name != "Backup of Weekly KPIs" {
+		t.Errorf("Expected project with Name=Backup of Weekly KPIs but got Name=%s", project.Name)
+	}
+}
+
+func TestProjectsUpdateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/projects/5ab3e4e0e8a4a6a7c7d0f113", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Projects.Update("5ab3e4e0e8a4a6a7c7d0f113", &analytics.Project{Name: "Backup of Weekly KPIs"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestProjectsDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/projects/5ab3e4e0e8a4a6a7c7d0f113"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.Projects.Delete("5ab3e4e0e8a4a6a7c7d0f113")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestProjectComponents(t *testing.T) {
+	raw, err := ioutil.ReadFile("./testdata/Projects.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var project analytics.Project
+	json.Unmarshal(raw, &project)
+
+	components, err := project.Components()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	want := &analytics.ProjectComponents{
+		Segments:          []string{"s300003364_589ce94be4b0c29f29c4f07f"},
+		Metrics:           []string{"metrics/pageviews"},
+		CalculatedMetrics: []string{"cm300003364_5ae7447df118f061698ddc31"},
+		Dimensions:        []string{"variables/page"},
+		DateRanges:        []string{"57a9ad685fe707f55ffb68f5"},
+	}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("Project components: %v, want %v", components, want)
+	}
+}
+
+func TestProjectComponentsDefinitionOrder(t *testing.T) {
+	project := analytics.Project{Definition: json.RawMessage(`{
+		"zPanel": {"metrics": [
+			{"__entity__": true, "type": "Metric", "id": "metrics/visits"},
+			{"id": "metrics/pageviews", "type": "Metric", "__entity__": true}
+		]},
+		"aPanel": {"metrics": [
+			{"__entity__": true, "type": "Metric", "id": "metrics/orders"},
+			{"__entity__": true, "type": "Metric", "id": "metrics/visits"}
+		]}
+	}`)}
+
+	components, err := project.Components()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	want := []string{"metrics/visits", "metrics/pageviews", "metrics/orders"}
+	if !reflect.DeepEqual(components.Metrics, want) {
+		t.Errorf("Project metrics: %v, want %v", components.Metrics, want)
+	}
+
+	project.Definition = json.RawMessage(`{"panels": [`)
+	if _, err := project.Components(); err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestProjectComponentsNested(t *testing.T) {
+	raw, err := ioutil.ReadFile("./testdata/Projects.Definition.Nested.json")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	project := analytics.Project{Definition: json.RawMessage(raw)}
+
+	components, err := project.Components()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if want := []string{"s300000000_1"}; !reflect.DeepEqual(components.Segments, want) {
+		t.Errorf("Project segments: %v, want %v", components.Segments, want)
+	}
+	if want := []string{"variables/page"}; !reflect.DeepEqual(components.Dimensions, want) {
+		t.Errorf("Project dimensions: %v, want %v", components.Dimensions, want)
+	}
+	if want := []string{"metrics/visits", "metrics/pageviews"}; !reflect.DeepEqual(components.Metrics, want) {
+		t.Errorf("Project metrics: %v, want %v", components.Metrics, want)
+	}
+}
+
+func TestProjectComponentsNoDefinition(t *testing.T) {
+	project := analytics.Project{ID: "5ab3e4e0e8a4a6a7c7d0f111"}
+
+	components, err := project.Components()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(components.Segments) != 0 || len(components.Metrics) != 0 {
+		t.Errorf("Expected no components but got %v", components)
+	}
+}
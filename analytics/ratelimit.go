@@ -0,0 +1,213 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter.
+// It allows bursts of up to burst requests and refills at rate requests per second.
+// A rate of 0 or less disables rate limiting.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter with a full bucket.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent and returns the time waited.
+func (l *RateLimiter) Wait() time.Duration {
+	wait, _ := l.WaitContext(context.Background())
+	return wait
+}
+
+// WaitContext blocks until a request may be sent or ctx is done and returns the time waited.
+// If ctx is done first, the reserved request is returned to the bucket and ctx.Err() is returned.
+func (l *RateLimiter) WaitContext(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	wait := l.reserve()
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+		l.cancel()
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket and returns the time until it is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.refill(time.Now())
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refill adds the tokens for the time since the last refill at the current rate, l.mu must be held.
+func (l *RateLimiter) refill(now time.Time) {
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// cancel returns a reserved token to the bucket.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate > 0 {
+		l.tokens++
+	}
+}
+
+// SetLimit changes the rate and burst of the rate limiter.
+// The time passed before the change is refilled at the previous rate.
+func (l *RateLimiter) SetLimit(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if burst < 1 {
+		burst = 1
+	}
+	l.rate = rate
+	l.burst = float64(burst)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// ClientStats holds the request statistics of a client
+type ClientStats struct {
+	Requests        int64
+	RateLimitWait   time.Duration
+	ConcurrencyWait time.Duration
+}
+
+// throttle limits the rate and concurrency of the requests of a client and records the time waited
+type throttle struct {
+	// accessed atomically, kept first for 64-bit alignment
+	requests        int64
+	rateLimitWait   int64
+	concurrencyWait int64
+
+	limiter *RateLimiter
+	slots   chan struct{}
+}
+
+// newThrottle returns a throttle for the rate limiter and max concurrency of the config.
+func newThrottle(config *Config) *throttle {
+	t := &throttle{limiter: config.RateLimiter}
+	if config.MaxConcurrency > 0 {
+		t.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+	return t
+}
+
+// acquire waits for the rate limiter and a free concurrency slot, or until ctx is done.
+// The returned function releases the slot and must be called once the request is done,
+// it is nil if ctx is done before a slot is acquired.
+func (t *throttle) acquire(ctx context.Context) (func(), error) {
+	atomic.AddInt64(&t.requests, 1)
+
+	if t.limiter != nil {
+		wait, err := t.limiter.WaitContext(ctx)
+		atomic.AddInt64(&t.rateLimitWait, int64(wait))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t.slots == nil {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		atomic.AddInt64(&t.concurrencyWait, int64(time.Since(start)))
+		return nil, ctx.Err()
+	}
+	atomic.AddInt64(&t.concurrencyWait, int64(time.Since(start)))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-t.slots })
+	}, nil
+}
+
+// stats returns the request statistics.
+func (t *throttle) stats() ClientStats {
+	return ClientStats{
+		Requests:        atomic.LoadInt64(&t.requests),
+		RateLimitWait:   time.Duration(atomic.LoadInt64(&t.rateLimitWait)),
+		ConcurrencyWait: time.Duration(atomic.LoadInt64(&t.concurrencyWait)),
+	}
+}
+
+// releasingBody is a response body that releases a concurrency slot when closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the concurrency slot.
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := analytics.NewRateLimiter(10, 2)
+
+	if wait := limiter.Wait(); wait != 0 {
+		t.Errorf("Expected no wait but waited %v", wait)
+	}
+	if wait := limiter.Wait(); wait != 0 {
+		t.Errorf("Expected no wait but waited %v", wait)
+	}
+	if wait := limiter.Wait(); wait < 50*time.Millisecond || wait > 100*time.Millisecond {
+		t.Errorf("Expected wait of about 100ms but waited %v", wait)
+	}
+}
+
+func TestRateLimiterSetLimit(t *testing.T) {
+	limiter := analytics.NewRateLimiter(0, 1)
+
+	for i := 0; i < 10; i++ {
+		if wait := limiter.Wait(); wait != 0 {
+			t.Errorf("Expected no wait but waited %v", wait)
+		}
+	}
+
+	limiter.SetLimit(100, 1)
+	if wait := limiter.Wait(); wait != 0 {
+		t.Errorf("Expected no wait but waited %v", wait)
+	}
+	if wait := limiter.Wait(); wait == 0 || wait > 10*time.Millisecond {
+		t.Errorf("Expected wait of about 10ms but waited %v", wait)
+	}
+}
+
+func TestRateLimiterSetLimitRefill(t *testing.T) {
+	limiter := analytics.NewRateLimiter(100, 3)
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+	}
+
+	// the bucket refilled at the previous rate is kept after lowering it
+	time.Sleep(50 * time.Millisecond)
+	limiter.SetLimit(0.01, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if wait, err := limiter.WaitContext(ctx); wait != 0 || err != nil {
+			t.Errorf("Expected no wait but waited %v (%v)", wait, err)
+		}
+	}
+	if _, err := limiter.WaitContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+
+	// the time before raising the rate is not credited at the new rate
+	limiter = analytics.NewRateLimiter(0.01, 5)
+	for i := 0; i < 5; i++ {
+		limiter.Wait()
+	}
+	time.Sleep(80 * time.Millisecond)
+	limiter.SetLimit(10, 5)
+	if wait := limiter.Wait(); wait < 50*time.Millisecond {
+		t.Errorf("Expected wait of about 100ms but waited %v", wait)
+	}
+}
+
+func TestRateLimiterWaitContext(t *testing.T) {
+	limiter := analytics.NewRateLimiter(10, 1)
+	limiter.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.WaitContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+
+	// the canceled request does not delay the next one
+	if wait := limiter.Wait(); wait > 100*time.Millisecond {
+		t.Errorf("Expected wait of at most 100ms but waited %v", wait)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// defaultReportCacheTTL is the default time to live of cached reports.
+const defaultReportCacheTTL = 30 * 24 * time.Hour
+
+// reportCacheKey returns the cache key of a ranked request, made of company ID and the hash of the request JSON.
+// Only requests with date ranges which ended before today are cacheable, other reports may still change.
+func (s *ReportsService) reportCacheKey(rankedRequest *RankedRequest, reqJSON []byte) (string, bool) {
+	if s.client.reportCache == nil || !isHistoricalRequest(rankedRequest, time.Now()) {
+		return "", false
+	}
+
+	hash := sha256.Sum256(reqJSON)
+	return cachePrefix(s.client.auth.companyID, "/reportcache") + "/" + hex.EncodeToString(hash[:]), true
+}
+
+// isHistoricalRequest returns true if all date ranges of the request ended by the start of the day before now.
+// The report suite time zone is not known, so the previous day is excluded as well.
+// Requests without date range or with date ranges which can't be parsed are not historical.
+func isHistoricalRequest(rankedRequest *RankedRequest, now time.Time) bool {
+	if rankedRequest == nil {
+		return false
+	}
+
+	var filters []RankedRequestReportFilter
+	if rankedRequest.GlobalFilters != nil {
+		filters = append(filters, *rankedRequest.GlobalFilters...)
+	}
+	if rankedRequest.MetricContainer != nil && rankedRequest.MetricContainer.MetricFilters != nil {
+		filters = append(filters, *rankedRequest.MetricContainer.MetricFilters...)
+	}
+
+	// date ranges are in the report suite time zone, compare the wall clock
+	year, month, day := now.Date()
+	cutoff := time.Date(year, month, day-1, 0, 0, 0, 0, time.UTC)
+
+	dateRanges := 0
+	for _, filter := range filters {
+		if filter.Type != "dateRange" {
+			continue
+		}
+		interval, err := ParseDateInterval(filter.DateRange)
+		if err != nil || interval.End.After(cutoff) {
+			return false
+		}
+		dateRanges++
+	}
+	return dateRanges > 0
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+	"math"
+)
+
+// RunComparison runs the passed RankedRequest for the current and the previous date range and joins the rows by item.
+// The date ranges replace any date range global filter of the request.
+// Rows are ordered like the current period, followed by the other items of the previous period.
+// Items of one period which are not part of the limited rows of the other period, e.g. items just outside
+// the row limit, are fetched for the other period with an item ID search. Metrics of an item which is not
+// part of a period even then count as 0 in the deltas. The relative delta of a metric is the fraction of
+// its previous value, e.g. 0.25 for an increase of 25%, and +Inf or -Inf if its previous value is 0 and its
+// current value is not.
+func (s *ReportsService) RunComparison(rankedRequest *RankedRequest, current, previous string) (*ComparisonReport, error) {
+	if rankedRequest == nil {
+		return nil, fmt.Errorf("missing ranked request")
+	}
+
+	currentReport, err := s.Run(withDateRange(rankedRequest, current))
+	if err != nil {
+		return nil, err
+	}
+	previousReport, err := s.Run(withDateRange(rankedRequest, previous))
+	if err != nil {
+		return nil, err
+	}
+
+	currentRows, err := s.fetchMissingRows(rankedRequest, current, currentReport, previousReport)
+	if err != nil {
+		return nil, err
+	}
+	previousRows, err := s.fetchMissingRows(rankedRequest, previous, previousReport, currentReport)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComparisonReport{
+		Current:  currentReport,
+		Previous: previousReport,
+		Rows:     compareRows(currentRows, previousRows),
+	}, nil
+}
+
+// RunPercentChange runs the passed RankedRequest with the percent change setting, which compares each item
+// to the previous period of the same length, and returns the rows as comparison.
+// Unlike RunComparison, only one report is run and the previous period is chosen by the API.
+// The relative delta of a metric is the percent change returned by the API, Current holds the current
+// metrics and Previous is nil. The absolute delta is derived from the current value and the percent change,
+// it is NaN if the previous value cannot be derived, i.e. if the current value is 0.
+func (s *ReportsService) RunPercentChange(rankedRequest *RankedRequest) (*ComparisonReport, error) {
+	if rankedRequest == nil {
+		return nil, fmt.Errorf("missing ranked request")
+	}
+
+	req := *rankedRequest
+	settings := RankedRequestSettings{}
+	if rankedRequest.Settings != nil {
+		settings = *rankedRequest.Settings
+	}
+	settings.IncludePercentChange = true
+	req.Settings = &settings
+
+	report, err := s.Run(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ComparisonRow
+	if report.Rows != nil {
+		for _, row := range *report.Rows {
+			comparison := ComparisonRow{
+				ItemID:        row.ItemID,
+				Value:         row.Value,
+				Current:       row.Data,
+				Delta:         make([]float64, len(row.PercentChange)),
+				RelativeDelta: make([]float64, len(row.PercentChange)),
+			}
+			for i, percentChange := range row.PercentChange {
+				var c float64
+				if i < len(row.Data) {
+					c = float64(row.Data[i])
+				}
+				r := float64(percentChange)
+				comparison.RelativeDelta[i] = r
+				switch {
+				case math.IsInf(r, 0):
+					// the previous value is 0
+					comparison.Delta[i] = c
+				case c == 0 || r == -1:
+					comparison.Delta[i] = math.NaN()
+				default:
+					comparison.Delta[i] = c - c/(1+r)
+				}
+			}
+			rows = append(rows, comparison)
+		}
+	}
+
+	return &ComparisonReport{Current: report, Rows: rows}, nil
+}
+
+// fetchMissingRows returns the rows of the report of a period, including the rows of the items of the other
+// report which the report is missing. The missing items are fetched with an item ID search.
+func (s *ReportsService) fetchMissingRows(rankedRequest *RankedRequest, dateRange string, report, other *RankedReportData) ([]RankedReportRowData, error) {
+	var rows []RankedReportRowData
+	present := map[string]bool{}
+	if report.Rows != nil {
+		rows = append(rows, *report.Rows...)
+		for _, row := range rows {
+			present[row.ItemID] = true
+		}
+	}
+
+	var missing []string
+	if other.Rows != nil {
+		for _, row := range *other.Rows {
+			if !present[row.ItemID] {
+				missing = append(missing, row.ItemID)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return rows, nil
+	}
+
+	// the item IDs narrow down the search of the request, its clause and excluded items still apply
+	req := withDateRange(rankedRequest, dateRange)
+	search := RankedRequestSearch{}
+	if rankedRequest.Search != nil {
+		search = *rankedRequest.Search
+	}
+	search.ItemIDs = missing
+	req.Search = &search
+	settings := RankedRequestSettings{}
+	if rankedRequest.Settings != nil {
+		settings = *rankedRequest.Settings
+	}
+	settings.Limit = len(missing)
+	settings.Page = 0
+	req.Settings = &settings
+
+	missingReport, err := s.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if missingReport.Rows != nil {
+		rows = append(rows, *missingReport.Rows...)
+	}
+	return rows, nil
+}
+
+// withDateRange returns a copy of the ranked request with the date range global filter replaced.
+func withDateRange(rankedRequest *RankedRequest, dateRange string) *RankedRequest {
+	req := *rankedRequest
+
+	filters := []RankedRequestReportFilter{{
+		Type:      "dateRange",
+		DateRange: dateRange,
+	}}
+	if rankedRequest.GlobalFilters != nil {
+		for _, filter := range *rankedRequest.GlobalFilters {
+			if filter.Type != "dateRange" {
+				filters = append(filters, filter)
+			}
+		}
+	}
+	req.GlobalFilters = &filters
+	return &req
+}
+
+// compareRows joins the rows of the current and previous period by item ID.
+func compareRows(current, previous []RankedReportRowData) []ComparisonRow {
+	var rows []ComparisonRow
+	index := map[string]int{}
+
+	for _, row := range current {
+		index[row.ItemID] = len(rows)
+		rows = append(rows, ComparisonRow{ItemID: row.ItemID, Value: row.Value, Current: row.Data})
+	}
+	for _, row := range previous {
+		i, ok := index[row.ItemID]
+		if !ok {
+			i = len(rows)
+			index[row.ItemID] = i
+			rows = append(rows, ComparisonRow{ItemID: row.ItemID, Value: row.Value})
+		}
+		rows[i].Previous = row.Data
+	}
+
+	for i := range rows {
+		rows[i].Delta, rows[i].RelativeDelta = compareData(rows[i].Current, rows[i].Previous)
+	}
+	return rows
+}
+
+// compareData returns the absolute and relative deltas of the current and previous metrics.
+func compareData(current, previous []float32) ([]float64, []float64) {
+	n := len(current)
+	if len(previous) > n {
+		n = len(previous)
+	}
+
+	delta := make([]float64, n)
+	relativeDelta := make([]float64, n)
+	for i := 0; i < n; i++ {
+		var c, p float64
+		if i < len(current) {
+			c = float64(current[i])
+		}
+		if i < len(previous) {
+			p = float64(previous[i])
+		}
+
+		delta[i] = c - p
+		switch {
+		case p != 0:
+			relativeDelta[i] = (c - p) / math.Abs(p)
+		case c > 0:
+			relativeDelta[i] = math.Inf(1)
+		case c < 0:
+			relativeDelta[i] = math.Inf(-1)
+		}
+	}
+	return delta, relativeDelta
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RunFreeformTable runs a ranked report for the passed freeform table and returns the rows labelled by column.
+func (s *ReportsService) RunFreeformTable(table *FreeformTable) (*FreeformTableData, error) {
+	rankedRequest, err := table.RankedRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.Run(rankedRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := table.labels()
+	data := &FreeformTableData{
+		Columns: labels,
+		Report:  report,
+	}
+	if report.Rows == nil {
+		return data, nil
+	}
+
+	// row data is ordered by the column IDs of the response, which are the column indexes
+	positions := make([]int, len(labels))
+	for i := range positions {
+		positions[i] = i
+	}
+	if report.Columns != nil {
+		for i, columnID := range report.Columns.ColumnIDs {
+			if column, err := strconv.Atoi(columnID); err == nil && column < len(positions) {
+				positions[column] = i
+			}
+		}
+	}
+
+	for _, row := range *report.Rows {
+		values := make(map[string]float32, len(labels))
+		for i, label := range labels {
+			if positions[i] < len(row.Data) {
+				values[label] = row.Data[positions[i]]
+			}
+		}
+		data.Rows = append(data.Rows, FreeformTableRow{
+			ItemID: row.ItemID,
+			Value:  row.Value,
+			Data:   values,
+		})
+	}
+	return data, nil
+}
+
+// RankedRequest returns the ranked report request of the freeform table.
+// Column IDs are the column indexes, metric filter IDs are generated for each distinct segment and date range.
+func (t *FreeformTable) RankedRequest() (*RankedRequest, error) {
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("missing columns")
+	}
+
+	seen := map[string]bool{}
+	for _, label := range t.labels() {
+		if seen[label] {
+			return nil, fmt.Errorf("duplicate column label %s", label)
+		}
+		seen[label] = true
+	}
+
+	var globalFilters []RankedRequestReportFilter
+	if t.DateRange != "" {
+		globalFilters = append(globalFilters, RankedRequestReportFilter{
+			Type:      "dateRange",
+			DateRange: t.DateRange,
+		})
+	}
+	for _, segmentID := range t.SegmentIDs {
+		globalFilters = append(globalFilters, RankedRequestReportFilter{
+			Type:      "segment",
+			SegmentID: segmentID,
+		})
+	}
+
+	var metricFilters []RankedRequestReportFilter
+	filterIDs := map[string]string{}
+	filterID := func(filter RankedRequestReportFilter) string {
+		key := filter.Type + "/" + filter.SegmentID + filter.DateRange
+		if id, ok := filterIDs[key]; ok {
+			return id
+		}
+		filter.ID = strconv.Itoa(len(metricFilters))
+		filterIDs[key] = filter.ID
+		metricFilters = append(metricFilters, filter)
+		return filter.ID
+	}
+
+	metrics := make([]RankedRequestReportMetric, 0, len(t.Columns))
+	for i, column := range t.Columns {
+		metric := RankedRequestReportMetric{
+			ID:       column.Metric,
+			ColumnID: strconv.Itoa(i),
+			Sort:     column.Sort,
+		}
+		if column.SegmentID != "" {
+			metric.Filters = append(metric.Filters, filterID(RankedRequestReportFilter{
+				Type:      "segment",
+				SegmentID: column.SegmentID,
+			}))
+		}
+		if column.DateRange != "" {
+			metric.Filters = append(metric.Filters, filterID(RankedRequestReportFilter{
+				Type:      "dateRange",
+				DateRange: column.DateRange,
+			}))
+		}
+		metrics = append(metrics, metric)
+	}
+
+	rankedRequest := &RankedRequest{
+		ReportSuiteID: t.ReportSuiteID,
+		Dimension:     t.Dimension,
+		Settings:      t.Settings,
+		MetricContainer: &RankedRequestReportMetrics{
+			Metrics: &metrics,
+		},
+	}
+	if len(globalFilters) > 0 {
+		rankedRequest.GlobalFilters = &globalFilters
+	}
+	if len(metricFilters) > 0 {
+		rankedRequest.MetricContainer.MetricFilters = &metricFilters
+	}
+	return rankedRequest, nil
+}
+
+// labels returns the column labels, a column without label is labelled by its metric.
+func (t *FreeformTable) labels() []string {
+	labels := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		labels[i] = column.Label
+		if labels[i] == "" {
+			labels[i] = column.Metric
+		}
+	}
+	return labels
+}
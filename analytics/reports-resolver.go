@@ -0,0 +1,107 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ItemResolver resolves item IDs of a dimension to their display values.
+// Resolved values are cached, so each item is looked up at most once.
+// It is safe for concurrent use.
+type ItemResolver struct {
+	reports   *ReportsService
+	rsid      string
+	dimension string
+	dateRange string
+	limit     int64
+
+	mu       sync.Mutex
+	values   map[string]string
+	nextPage int64
+	lastPage bool
+}
+
+// NewItemResolver returns a new ItemResolver for the dimension of a report suite.
+// Items are looked up with TopItems in pages of the given limit within the optional date range.
+func (s *ReportsService) NewItemResolver(rsid, dimension, dateRange string, limit int64) *ItemResolver {
+	return &ItemResolver{
+		reports:   s,
+		rsid:      rsid,
+		dimension: dimension,
+		dateRange: dateRange,
+		limit:     limit,
+		values:    map[string]string{},
+	}
+}
+
+// Add adds known item values to the cache, e.g. the rows of a ranked report.
+func (r *ItemResolver) Add(rows []RankedReportRowData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, row := range rows {
+		r.values[row.ItemID] = row.Value
+	}
+}
+
+// Resolve returns the display value of an item ID.
+func (r *ItemResolver) Resolve(itemID string) (string, error) {
+	values, err := r.ResolveAll([]string{itemID})
+	if err != nil {
+		return "", err
+	}
+	return values[itemID], nil
+}
+
+// ResolveAll returns the display values of the passed item IDs.
+// Uncached items are looked up page by page until all are found or no more items are available.
+func (r *ItemResolver) ResolveAll(itemIDs []string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for !r.lastPage && len(r.missing(itemIDs)) > 0 {
+		items, err := r.reports.TopItems(r.rsid, r.dimension, "", r.dateRange, r.limit, r.nextPage)
+		if err != nil {
+			return nil, err
+		}
+		if items.Rows != nil {
+			for _, row := range *items.Rows {
+				r.values[row.ItemID] = row.Value
+			}
+		}
+		r.nextPage++
+		r.lastPage = items.LastPage || items.Rows == nil || len(*items.Rows) == 0
+	}
+
+	if missing := r.missing(itemIDs); len(missing) > 0 {
+		return nil, fmt.Errorf("unknown items %v of dimension %s", missing, r.dimension)
+	}
+
+	values := make(map[string]string, len(itemIDs))
+	for _, itemID := range itemIDs {
+		values[itemID] = r.values[itemID]
+	}
+	return values, nil
+}
+
+// missing returns the item IDs not in the cache.
+func (r *ItemResolver) missing(itemIDs []string) []string {
+	var missing []string
+	for _, itemID := range itemIDs {
+		if _, ok := r.values[itemID]; !ok {
+			missing = append(missing, itemID)
+		}
+	}
+	return missing
+}
@@ -209,3 +209,138 @@ type RankedReportData struct {
 	Rows             *[]RankedReportRowData      `json:"rows,omitempty"`
 	SummaryData      *RankedReportSummaryData    `json:"summaryData,omitempty"`
 }
+
+// TopItemsData represents the top items of a dimension
+type TopItemsData struct {
+	TotalPages       int                         `json:"totalPages,omitempty"`
+	FirstPage        bool                        `json:"firstPage"`
+	LastPage         bool                        `json:"lastPage"`
+	NumberOfElements int                         `json:"numberOfElements,omitempty"`
+	Number           int                         `json:"number,omitempty"`
+	TotalElements    int                         `json:"totalElements"`
+	Columns          *RankedReportColumnMetaData `json:"columns,omitempty"`
+	Rows             *[]RankedReportRowData      `json:"rows,omitempty"`
+}
+
+// Realtime request types
+
+// RealtimeRequestDimension represents a realtime report dimension
+type RealtimeRequestDimension struct {
+	Dimension         string `json:"dimension"`
+	DimensionColumnID string `json:"dimensionColumnId,omitempty"`
+}
+
+// RealtimeRequestSettings represents the realtime request settings
+type RealtimeRequestSettings struct {
+	RealTimeMinuteGranularity  int  `json:"realTimeMinuteGranularity,omitempty"`
+	RealTimeReturnColumnTotals bool `json:"realTimeReturnColumnTotals,omitempty"`
+	Limit                      int  `json:"limit,omitempty"`
+	IncludeAnomalyDetection    bool `json:"includeAnomalyDetection,omitempty"`
+}
+
+// RealtimeRequest represents a realtime report request.
+// The date range global filter is minute based, e.g. "2020-09-01T10:00:00/2020-09-01T11:00:00".
+// Use the dimension "variables/daterangeminute" to trend metrics over time.
+type RealtimeRequest struct {
+	ReportSuiteID   string                       `json:"rsid"`
+	GlobalFilters   *[]RankedRequestReportFilter `json:"globalFilters,omitempty"`
+	MetricContainer *RankedRequestReportMetrics  `json:"metricContainer,omitempty"`
+	Dimensions      *[]RealtimeRequestDimension  `json:"dimensions,omitempty"`
+	Settings        *RealtimeRequestSettings     `json:"settings,omitempty"`
+}
+
+// Realtime response types
+
+// RealtimeReportColumnMetaData represents the realtime report column meta data
+type RealtimeReportColumnMetaData struct {
+	Dimensions   *[]RankedReportDimension   `json:"dimensions,omitempty"`
+	ColumnIDs    []string                   `json:"columnIds,omitempty"`
+	ColumnErrors *[]RankedReportColumnError `json:"columnErrors,omitempty"`
+}
+
+// RealtimeReportRowDimension represents the item of a dimension in a realtime report row
+type RealtimeReportRowDimension struct {
+	DimensionColumnID string `json:"dimensionColumnId,omitempty"`
+	ItemID            string `json:"itemId,omitempty"`
+	Value             string `json:"value,omitempty"`
+}
+
+// RealtimeReportRowData represents the realtime report row data
+type RealtimeReportRowData struct {
+	Dimensions          []RealtimeReportRowDimension `json:"dimensions,omitempty"`
+	Data                []float32                    `json:"data,omitempty"`
+	DataExpected        []float32                    `json:"dataExpected,omitempty"`
+	DataUpperBound      []float32                    `json:"dataUpperBound,omitempty"`
+	DataLowerBound      []float32                    `json:"dataLowerBound,omitempty"`
+	DataAnomalyDetected []bool                       `json:"dataAnomalyDetected,omitempty"`
+}
+
+// RealtimeReportData represents the realtime report data
+type RealtimeReportData struct {
+	Columns     *RealtimeReportColumnMetaData  `json:"columns,omitempty"`
+	Rows        *[]RealtimeReportRowData       `json:"rows,omitempty"`
+	SummaryData *RankedReportSummaryDataTotals `json:"summaryData,omitempty"`
+}
+
+// RealtimeBucket represents the rows of a single time bucket of a realtime report
+type RealtimeBucket struct {
+	ItemID string
+	Value  string
+	Rows   []RealtimeReportRowData
+}
+
+// Freeform table types
+
+// FreeformColumn represents a metric column of a freeform table.
+// SegmentID and DateRange optionally filter the column.
+type FreeformColumn struct {
+	Label     string
+	Metric    string
+	SegmentID string
+	DateRange string
+	Sort      string
+}
+
+// FreeformTable represents a freeform table with a dimension in the rows and metrics in the columns
+type FreeformTable struct {
+	ReportSuiteID string
+	Dimension     string
+	DateRange     string
+	SegmentIDs    []string
+	Columns       []FreeformColumn
+	Settings      *RankedRequestSettings
+}
+
+// FreeformTableRow represents a freeform table row with the metric values keyed by column label
+type FreeformTableRow struct {
+	ItemID string
+	Value  string
+	Data   map[string]float32
+}
+
+// FreeformTableData represents the result of a freeform table
+type FreeformTableData struct {
+	Columns []string
+	Rows    []FreeformTableRow
+	Report  *RankedReportData
+}
+
+// Comparison types
+
+// ComparisonRow represents a row of a period-over-period comparison.
+// Current and Previous are nil if the item is not part of the respective period.
+type ComparisonRow struct {
+	ItemID        string
+	Value         string
+	Current       []float32
+	Previous      []float32
+	Delta         []float64
+	RelativeDelta []float64
+}
+
+// ComparisonReport represents a period-over-period comparison of a ranked report
+type ComparisonReport struct {
+	Current  *RankedReportData
+	Previous *RankedReportData
+	Rows     []ComparisonRow
+}
@@ -12,8 +12,13 @@ governing permissions and limitations under the License.
 package analytics
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ReportsService handles reports.
@@ -23,15 +28,188 @@ type ReportsService struct {
 }
 
 // Run runs a report for the passed RankedRequest.
+// If the client has a ReportCache, reports of date ranges which ended before today are served from the cache.
 // API docs: https://adobedocs.github.io/analytics-2.0-apis/#/reports
 func (s *ReportsService) Run(rankedRequest *RankedRequest) (*RankedReportData, error) {
 	reqJSON, _ := json.Marshal(rankedRequest)
 	reqBody := strings.NewReader(string(reqJSON))
 
 	var data RankedReportData
+	key, cacheable := s.reportCacheKey(rankedRequest, reqJSON)
+	if cacheable {
+		if cached, ok := s.client.reportCache.Get(key); ok {
+			err := json.Unmarshal(cached, &data)
+			if err == nil {
+				return &data, nil
+			}
+		}
+	}
+
 	err := s.client.post("/reports", map[string]string{}, reqBody, &data)
 	if err != nil {
 		return nil, err
 	}
+
+	if cacheable {
+		if dataJSON, err := json.Marshal(&data); err == nil {
+			s.client.reportCache.Set(key, dataJSON, s.client.reportCacheTTL)
+		}
+	}
+	return &data, err
+}
+
+// TopItems returns the top items of a dimension, e.g. to look up item IDs and values.
+// search is an optional search clause, e.g. "CONTAINS 'checkout'".
+// dateRange is an optional ISO 8601 interval, e.g. "2020-09-01T00:00:00.000/2020-10-01T00:00:00.000".
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/reports/getTopItems
+func (s *ReportsService) TopItems(rsid, dimension, search, dateRange string, limit, page int64) (*TopItemsData, error) {
+	var params = map[string]string{}
+	params["rsid"] = rsid
+	params["dimension"] = dimension
+	if search != "" {
+		params["search-clause"] = search
+	}
+	if dateRange != "" {
+		params["dateRange"] = dateRange
+	}
+	params["limit"] = strconv.FormatInt(limit, 10)
+	params["page"] = strconv.FormatInt(page, 10)
+
+	var data TopItemsData
+	err := s.client.get("/reports/topItems", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
 	return &data, err
 }
+
+// RunRealtime runs a realtime report for the passed RealtimeRequest.
+// A realtime report supports up to three dimensions.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/reports/getRealTimeReport
+func (s *ReportsService) RunRealtime(realtimeRequest *RealtimeRequest) (*RealtimeReportData, error) {
+	if realtimeRequest != nil && realtimeRequest.Dimensions != nil && len(*realtimeRequest.Dimensions) > 3 {
+		return nil, fmt.Errorf("realtime reports support up to 3 dimensions, got %d", len(*realtimeRequest.Dimensions))
+	}
+
+	reqJSON, err := json.Marshal(realtimeRequest)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data RealtimeReportData
+	err = s.client.post("/reports/realtime", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// PollRealtime runs the passed RealtimeRequest every interval and sends each completed time bucket to the
+// returned bucket channel once. Rows are grouped into buckets by the item of their first dimension, which
+// should be a time dimension like "variables/daterangeminute". The most recent bucket is still filling up
+// and is only sent once a newer bucket appears.
+// The requests are sent with ctx, so canceling ctx also aborts a request in flight.
+// Polling stops when ctx is done or a request fails. Failed requests are not retried: the error is sent to
+// the returned error channel and the caller may poll again, e.g. after a 429 status code. Use a HTTP client
+// with retries to keep polling through transient errors. No error is sent if polling stops because ctx is done.
+// Both channels are closed when polling stops. A non-positive interval sends an error without polling.
+func (s *ReportsService) PollRealtime(ctx context.Context, realtimeRequest *RealtimeRequest, interval time.Duration) (<-chan RealtimeBucket, <-chan error) {
+	buckets := make(chan RealtimeBucket)
+	errs := make(chan error, 1)
+
+	if interval <= 0 {
+		errs <- fmt.Errorf("invalid interval %v", interval)
+		close(errs)
+		close(buckets)
+		return buckets, errs
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(buckets)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		reports := s.client.WithContext(ctx).Reports
+		var last string
+		for {
+			data, err := reports.RunRealtime(realtimeRequest)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+
+			for _, bucket := range completedRealtimeBuckets(data, last) {
+				select {
+				case buckets <- bucket:
+					last = bucket.ItemID
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return buckets, errs
+}
+
+// completedRealtimeBuckets groups the report rows into time buckets and returns the completed buckets after the passed item ID.
+func completedRealtimeBuckets(data *RealtimeReportData, after string) []RealtimeBucket {
+	if data.Rows == nil {
+		return nil
+	}
+
+	var buckets []RealtimeBucket
+	index := map[string]int{}
+	for _, row := range *data.Rows {
+		if len(row.Dimensions) == 0 {
+			continue
+		}
+		item := row.Dimensions[0]
+		i, ok := index[item.ItemID]
+		if !ok {
+			i = len(buckets)
+			index[item.ItemID] = i
+			buckets = append(buckets, RealtimeBucket{ItemID: item.ItemID, Value: item.Value})
+		}
+		buckets[i].Rows = append(buckets[i].Rows, row)
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return compareItemIDs(buckets[i].ItemID, buckets[j].ItemID) < 0
+	})
+
+	// the most recent bucket is incomplete
+	if len(buckets) > 0 {
+		buckets = buckets[:len(buckets)-1]
+	}
+
+	completed := buckets[:0]
+	for _, bucket := range buckets {
+		if after == "" || compareItemIDs(bucket.ItemID, after) > 0 {
+			completed = append(completed, bucket)
+		}
+	}
+	return completed
+}
+
+// compareItemIDs compares numeric item IDs of different length, e.g. time items like "1200901100500".
+func compareItemIDs(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
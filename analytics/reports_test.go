@@ -12,11 +12,14 @@ governing permissions and limitations under the License.
 package analytics_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/adobe/aa-client-go/analytics"
 )
@@ -70,3 +73,595 @@ func TestReportsRunError(t *testing.T) {
 		t.Errorf("Expected error but got none")
 	}
 }
+
+func TestReportsRunCached(t *testing.T) {
+	setup()
+	defer teardown()
+
+	req, err := ioutil.ReadFile("./testdata/Reports.Run.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile("./testdata/Reports.Run.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	calls := 0
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, string(raw))
+	})
+
+	config := *testConfig
+	config.ReportCache = analytics.NewLRUCache(10)
+	client, err := analytics.NewClient(&config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// historical report is cached
+	for i := 0; i < 2; i++ {
+		var rankedRequest analytics.RankedRequest
+		json.Unmarshal(req, &rankedRequest)
+
+		report, err := client.Reports.Run(&rankedRequest)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if len(*report.Rows) != 68 {
+			t.Errorf("Expected %d report rows but got %d", 68, len(*report.Rows))
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected %d requests but got %d", 1, calls)
+	}
+
+	// report including today is not cached
+	today := time.Now().Format("2006-01-02")
+	for i := 0; i < 2; i++ {
+		var rankedRequest analytics.RankedRequest
+		json.Unmarshal(req, &rankedRequest)
+		(*rankedRequest.GlobalFilters)[0].DateRange = "2020-04-01T00:00:00.000/" + today + "T23:59:59.999"
+
+		_, err := client.Reports.Run(&rankedRequest)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Expected %d requests but got %d", 3, calls)
+	}
+}
+
+func TestReportsTopItems(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/reports/topItems"
+
+	raw, err := ioutil.ReadFile("./testdata/Reports.TopItems.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"rsid":          "amc.aem.prod",
+			"dimension":     "variables/page",
+			"search-clause": "CONTAINS 'e'",
+			"dateRange":     "2020-09-01T00:00:00.000/2020-10-01T00:00:00.000",
+			"limit":         "2",
+			"page":          "0",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	items, err := testClient.Reports.TopItems("amc.aem.prod", "variables/page", "CONTAINS 'e'", "2020-09-01T00:00:00.000/2020-10-01T00:00:00.000", 2, 0)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*items.Rows) != 2 {
+		t.Errorf("Expected %d items but got %d", 2, len(*items.Rows))
+	}
+}
+
+func TestReportsTopItemsError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/reports/topItems", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Reports.TopItems("amc.aem.prod", "variables/page", "", "", 2, 0)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestItemResolver(t *testing.T) {
+	setup()
+	defer teardown()
+
+	page0, err := ioutil.ReadFile("./testdata/Reports.TopItems.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	page1, err := ioutil.ReadFile("./testdata/Reports.TopItems.Page1.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	calls := 0
+	testMux.HandleFunc(baseURL+"/reports/topItems", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("page") == "0" {
+			fmt.Fprint(w, string(page0))
+		} else {
+			fmt.Fprint(w, string(page1))
+		}
+	})
+
+	resolver := testClient.Reports.NewItemResolver("amc.aem.prod", "variables/page", "", 2)
+	resolver.Add([]analytics.RankedReportRowData{{ItemID: "1", Value: "known"}})
+
+	value, err := resolver.Resolve("1")
+	if err != nil || value != "known" {
+		t.Errorf("Resolve: %q (%v), want %q", value, err, "known")
+	}
+	if calls != 0 {
+		t.Errorf("Expected %d calls but got %d", 0, calls)
+	}
+
+	values, err := resolver.ResolveAll([]string{"2283524561", "3418291770"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if values["2283524561"] != "home" || values["3418291770"] != "order confirmation" {
+		t.Errorf("Unexpected values %v", values)
+	}
+	if calls != 2 {
+		t.Errorf("Expected %d calls but got %d", 2, calls)
+	}
+
+	value, err = resolver.Resolve("1862367542")
+	if err != nil || value != "checkout" {
+		t.Errorf("Resolve: %q (%v), want %q", value, err, "checkout")
+	}
+	if calls != 2 {
+		t.Errorf("Expected cached value but got %d calls", calls)
+	}
+
+	_, err = resolver.Resolve("0")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+	if calls != 2 {
+		t.Errorf("Expected no further calls but got %d calls", calls)
+	}
+}
+
+func TestReportsRunRealtime(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/reports/realtime"
+
+	req, err := ioutil.ReadFile("./testdata/Reports.RunRealtime.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var realtimeRequest analytics.RealtimeRequest
+	json.Unmarshal(req, &realtimeRequest)
+
+	raw, err := ioutil.ReadFile("./testdata/Reports.RunRealtime.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, req)
+		fmt.Fprint(w, string(raw))
+	})
+
+	report, err := testClient.Reports.RunRealtime(&realtimeRequest)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*report.Rows) != 4 {
+		t.Errorf("Expected %d report rows but got %d", 4, len(*report.Rows))
+	}
+
+	if got := (*report.Rows)[1].Dimensions[1].Value; got != "checkout" {
+		t.Errorf("Expected row dimension value %s but got %s", "checkout", got)
+	}
+}
+
+func TestReportsRunRealtimeError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/reports/realtime", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Reports.RunRealtime(&analytics.RealtimeRequest{ReportSuiteID: "amc.aem.prod"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportsRunRealtimeTooManyDimensions(t *testing.T) {
+	_, err := testClient.Reports.RunRealtime(&analytics.RealtimeRequest{
+		ReportSuiteID: "amc.aem.prod",
+		Dimensions: &[]analytics.RealtimeRequestDimension{
+			{Dimension: "variables/daterangeminute"},
+			{Dimension: "variables/page"},
+			{Dimension: "variables/browser"},
+			{Dimension: "variables/geocountry"},
+		},
+	})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportsPollRealtime(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responses := []string{
+		`{"rows": [
+			{"dimensions": [{"itemId": "1200801100000", "value": "10:00"}], "data": [1]},
+			{"dimensions": [{"itemId": "1200801100500", "value": "10:05"}], "data": [2]}
+		]}`,
+		`{"rows": [
+			{"dimensions": [{"itemId": "1200801100000", "value": "10:00"}], "data": [1]},
+			{"dimensions": [{"itemId": "1200801100500", "value": "10:05"}], "data": [3]}
+		]}`,
+		`{"rows": [
+			{"dimensions": [{"itemId": "1200801100500", "value": "10:05"}], "data": [4]},
+			{"dimensions": [{"itemId": "1200801101000", "value": "10:10"}], "data": [5]},
+			{"dimensions": [{"itemId": "1200801101500", "value": "10:15"}], "data": [6]}
+		]}`,
+	}
+	calls := 0
+	testMux.HandleFunc(baseURL+"/reports/realtime", func(w http.ResponseWriter, r *http.Request) {
+		if calls >= len(responses) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, responses[calls])
+		calls++
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	buckets, errs := testClient.Reports.PollRealtime(ctx, &analytics.RealtimeRequest{ReportSuiteID: "amc.aem.prod"}, time.Millisecond)
+
+	var got []string
+	for bucket := range buckets {
+		got = append(got, fmt.Sprintf("%s=%v", bucket.Value, bucket.Rows[0].Data[0]))
+	}
+
+	want := []string{"10:00=1", "10:05=4", "10:10=5"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Buckets: %v, want %v", got, want)
+	}
+
+	if err := <-errs; err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportsPollRealtimeCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the request is in flight when polling is canceled
+	release := make(chan struct{})
+	defer close(release)
+	testMux.HandleFunc(baseURL+"/reports/realtime", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-release
+	})
+
+	buckets, errs := testClient.Reports.PollRealtime(ctx, &analytics.RealtimeRequest{ReportSuiteID: "amc.aem.prod"}, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		for range buckets {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected polling to stop")
+	}
+
+	if err := <-errs; err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
+}
+
+func TestReportsPollRealtimeInterval(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc(baseURL+"/reports/realtime", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	buckets, errs := testClient.Reports.PollRealtime(context.Background(), &analytics.RealtimeRequest{ReportSuiteID: "amc.aem.prod"}, 0)
+	for range buckets {
+		t.Errorf("Expected no buckets")
+	}
+	if err := <-errs; err == nil || err.Error() != "invalid interval 0s" {
+		t.Errorf("Expected interval error but got %v", err)
+	}
+	if _, ok := <-errs; ok {
+		t.Errorf("Expected closed error channel")
+	}
+	if calls != 0 {
+		t.Errorf("Expected 0 requests but got %d", calls)
+	}
+}
+
+func TestReportsRunFreeformTable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/reports"
+
+	req, err := ioutil.ReadFile("./testdata/Reports.RunFreeformTable.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile("./testdata/Reports.RunFreeformTable.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, req)
+		fmt.Fprint(w, string(raw))
+	})
+
+	table, err := testClient.Reports.RunFreeformTable(&analytics.FreeformTable{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+		DateRange:     "2020-09-01T00:00:00.000/2020-10-01T00:00:00.000",
+		SegmentIDs:    []string{"s300003364_589ce94be4b0c29f29c4f07f"},
+		Columns: []analytics.FreeformColumn{
+			{Metric: "metrics/pageviews", Sort: "desc"},
+			{Label: "Mobile Page Views", Metric: "metrics/pageviews", SegmentID: "Visits_from_Mobile_Devices"},
+			{Label: "Mobile Visits Last Month", Metric: "metrics/visits", SegmentID: "Visits_from_Mobile_Devices", DateRange: "2020-08-01T00:00:00.000/2020-09-01T00:00:00.000"},
+			{Label: "Visits Last Month", Metric: "metrics/visits", DateRange: "2020-08-01T00:00:00.000/2020-09-01T00:00:00.000"},
+		},
+		Settings: &analytics.RankedRequestSettings{Limit: 2},
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	want := []string{"metrics/pageviews", "Mobile Page Views", "Mobile Visits Last Month", "Visits Last Month"}
+	if fmt.Sprint(table.Columns) != fmt.Sprint(want) {
+		t.Errorf("Columns: %v, want %v", table.Columns, want)
+	}
+
+	if len(table.Rows) != 2 {
+		t.Fatalf("Expected %d rows but got %d", 2, len(table.Rows))
+	}
+
+	if got := table.Rows[1].Data["Mobile Visits Last Month"]; got != 40 {
+		t.Errorf("Expected %s of %s to be %v but got %v", "Mobile Visits Last Month", table.Rows[1].Value, 40, got)
+	}
+}
+
+func TestReportsRunFreeformTableInvalid(t *testing.T) {
+	tables := []analytics.FreeformTable{
+		{ReportSuiteID: "amc.aem.prod", Dimension: "variables/page"},
+		{ReportSuiteID: "amc.aem.prod", Dimension: "variables/page", Columns: []analytics.FreeformColumn{
+			{Metric: "metrics/visits"},
+			{Metric: "metrics/visits", SegmentID: "Visits_from_Mobile_Devices"},
+		}},
+	}
+
+	for _, table := range tables {
+		_, err := testClient.Reports.RunFreeformTable(&table)
+		if err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	}
+}
+
+func TestReportsRunFreeformTableError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Reports.RunFreeformTable(&analytics.FreeformTable{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+		Columns:       []analytics.FreeformColumn{{Metric: "metrics/visits"}},
+	})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportsRunComparison(t *testing.T) {
+	setup()
+	defer teardown()
+
+	current := "2020-09-07T00:00:00.000/2020-09-14T00:00:00.000"
+	previous := "2020-08-31T00:00:00.000/2020-09-07T00:00:00.000"
+
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		var rankedRequest analytics.RankedRequest
+		json.NewDecoder(r.Body).Decode(&rankedRequest)
+
+		filters := *rankedRequest.GlobalFilters
+		if len(filters) != 2 || filters[1].SegmentID != "s300003364_589ce94be4b0c29f29c4f07f" {
+			t.Errorf("Unexpected global filters %v", filters)
+		}
+
+		if rankedRequest.Search == nil || rankedRequest.Search.Clause != "CONTAINS 'e'" {
+			t.Errorf("Expected search clause of the request but got %+v", rankedRequest.Search)
+			return
+		}
+
+		// items missing from the rows of a period are fetched by item ID
+		if len(rankedRequest.Search.ItemIDs) > 0 {
+			ids := fmt.Sprint(rankedRequest.Search.ItemIDs)
+			switch {
+			case filters[0].DateRange == current && ids == "[3]":
+				// the item is just outside the row limit of the current period
+				fmt.Fprint(w, `{"rows": [{"itemId": "3", "value": "search", "data": [5, 1]}]}`)
+			case filters[0].DateRange == previous && ids == "[2]":
+				fmt.Fprint(w, `{"rows": []}`)
+			default:
+				t.Errorf("Unexpected item search %s for %s", ids, filters[0].DateRange)
+			}
+			return
+		}
+
+		switch filters[0].DateRange {
+		case current:
+			fmt.Fprint(w, `{"rows": [
+				{"itemId": "1", "value": "home", "data": [150, 10]},
+				{"itemId": "2", "value": "checkout", "data": [30, 0]}
+			]}`)
+		case previous:
+			fmt.Fprint(w, `{"rows": [
+				{"itemId": "3", "value": "search", "data": [20, 4]},
+				{"itemId": "1", "value": "home", "data": [100, 10]}
+			]}`)
+		default:
+			t.Errorf("Unexpected date range %s", filters[0].DateRange)
+		}
+	})
+
+	rankedRequest := &analytics.RankedRequest{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+		GlobalFilters: &[]analytics.RankedRequestReportFilter{
+			{Type: "dateRange", DateRange: "2020-01-01T00:00:00.000/2020-01-02T00:00:00.000"},
+			{Type: "segment", SegmentID: "s300003364_589ce94be4b0c29f29c4f07f"},
+		},
+		Search: &analytics.RankedRequestSearch{Clause: "CONTAINS 'e'"},
+	}
+	report, err := testClient.Reports.RunComparison(rankedRequest, current, previous)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// the request of the caller is not changed
+	if rankedRequest.Search.ItemIDs != nil || rankedRequest.Settings != nil ||
+		(*rankedRequest.GlobalFilters)[0].DateRange != "2020-01-01T00:00:00.000/2020-01-02T00:00:00.000" {
+		t.Errorf("Unexpected change of the request %+v", rankedRequest)
+	}
+
+	if len(report.Rows) != 3 {
+		t.Fatalf("Expected %d rows but got %d", 3, len(report.Rows))
+	}
+
+	home := report.Rows[0]
+	if home.Value != "home" || home.Delta[0] != 50 || home.RelativeDelta[0] != 0.5 || home.RelativeDelta[1] != 0 {
+		t.Errorf("Unexpected row %+v", home)
+	}
+
+	checkout := report.Rows[1]
+	if checkout.Previous != nil || checkout.Delta[0] != 30 || !math.IsInf(checkout.RelativeDelta[0], 1) || checkout.RelativeDelta[1] != 0 {
+		t.Errorf("Unexpected row %+v", checkout)
+	}
+
+	search := report.Rows[2]
+	if search.Current == nil || search.Delta[1] != -3 || search.RelativeDelta[1] != -0.75 {
+		t.Errorf("Unexpected row %+v", search)
+	}
+}
+
+func TestReportsRunPercentChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		var rankedRequest analytics.RankedRequest
+		json.NewDecoder(r.Body).Decode(&rankedRequest)
+		if rankedRequest.Settings == nil || !rankedRequest.Settings.IncludePercentChange || rankedRequest.Settings.Limit != 10 {
+			t.Errorf("Unexpected settings %+v", rankedRequest.Settings)
+		}
+
+		fmt.Fprint(w, `{"rows": [
+			{"itemId": "1", "value": "home", "data": [150, 0], "percentChange": [0.5, -1]}
+		]}`)
+	})
+
+	report, err := testClient.Reports.RunPercentChange(&analytics.RankedRequest{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+		Settings:      &analytics.RankedRequestSettings{Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if len(report.Rows) != 1 {
+		t.Fatalf("Expected %d rows but got %d", 1, len(report.Rows))
+	}
+	home := report.Rows[0]
+	if home.Previous != nil || home.Delta[0] != 50 || home.RelativeDelta[0] != 0.5 || !math.IsNaN(home.Delta[1]) {
+		t.Errorf("Unexpected row %+v", home)
+	}
+
+	_, err = testClient.Reports.RunPercentChange(nil)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportsRunComparisonError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/reports", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Reports.RunComparison(&analytics.RankedRequest{ReportSuiteID: "amc.aem.prod"},
+		"2020-09-07T00:00:00.000/2020-09-14T00:00:00.000", "2020-08-31T00:00:00.000/2020-09-07T00:00:00.000")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+
+	_, err = testClient.Reports.RunComparison(nil, "", "")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+// Share recipient types
+const (
+	ShareToTypeUser  = "user"
+	ShareToTypeGroup = "group"
+	ShareToTypeAll   = "all"
+)
+
+// Request types
+
+// ShareRequest represents a request to share a component
+type ShareRequest struct {
+	ComponentType string `json:"componentType"`
+	ComponentID   string `json:"componentId"`
+	ShareToID     int    `json:"shareToId"`
+	ShareToType   string `json:"shareToType"`
+}
+
+// ComponentSharesSearch represents a search for the shares of components
+type ComponentSharesSearch struct {
+	ComponentType string   `json:"componentType"`
+	ComponentIDs  []string `json:"componentIds"`
+}
+
+// Response types
+
+// Share represents a component share
+type Share struct {
+	ShareID            int    `json:"shareId,omitempty"`
+	ShareToID          int    `json:"shareToId"`
+	ShareToType        string `json:"shareToType,omitempty"`
+	ShareToDisplayName string `json:"shareToDisplayName,omitempty"`
+	ComponentType      string `json:"componentType,omitempty"`
+	ComponentID        string `json:"componentId,omitempty"`
+}
+
+// ComponentShares represents the shares of a component
+type ComponentShares struct {
+	ComponentType string   `json:"componentType,omitempty"`
+	ComponentID   string   `json:"componentId,omitempty"`
+	Shares        *[]Share `json:"shares,omitempty"`
+}
+
+// Shares represents a page of shares
+type Shares struct {
+	Content          *[]Share `json:"content,omitempty"`
+	Number           int      `json:"number"`
+	Size             int      `json:"size"`
+	NumberOfElements int      `json:"numberOfElements"`
+	TotalElements    int      `json:"totalElements"`
+	PreviousPage     bool     `json:"previousPage"`
+	FirstPage        bool     `json:"firstPage"`
+	NextPage         bool     `json:"nextPage"`
+	LastPage         bool     `json:"lastPage"`
+	Sort             *[]Sort  `json:"sort,omitempty"`
+	TotalPages       int      `json:"totalPages"`
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SharesService handles component shares.
+// Analytics docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares
+type SharesService struct {
+	client *Client
+}
+
+// GetAll returns a list of shares for the current user.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/getAllShares
+func (s *SharesService) GetAll(limit, page int64) (*Shares, error) {
+	var params = map[string]string{}
+	params["limit"] = strconv.FormatInt(limit, 10)
+	params["page"] = strconv.FormatInt(page, 10)
+
+	var data Shares
+	err := s.client.get("/componentmetadata/shares", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetByID returns a single share.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/getShare
+func (s *SharesService) GetByID(id int) (*Share, error) {
+	var data Share
+	err := s.client.get(fmt.Sprintf("/componentmetadata/shares/%d", id), map[string]string{}, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetByComponentIDs returns the shares of the given components.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/findByComponentTypeAndIds
+func (s *SharesService) GetByComponentIDs(componentType string, componentIDs []string) (*[]ComponentShares, error) {
+	reqJSON, err := json.Marshal(&ComponentSharesSearch{
+		ComponentType: componentType,
+		ComponentIDs:  componentIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data []ComponentShares
+	err = s.client.post("/componentmetadata/shares/component/search", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Share shares a component with the passed recipient.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/share
+func (s *SharesService) Share(shareRequest *ShareRequest) (*Share, error) {
+	reqJSON, err := json.Marshal(shareRequest)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data Share
+	err = s.client.create("/componentmetadata/shares", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// ShareWithUser shares a component with an Analytics user.
+func (s *SharesService) ShareWithUser(componentType, componentID string, user *User) (*Share, error) {
+	return s.Share(&ShareRequest{
+		ComponentType: componentType,
+		ComponentID:   componentID,
+		ShareToID:     user.LoginID,
+		ShareToType:   ShareToTypeUser,
+	})
+}
+
+// ShareWithOwner shares a component with the owner of another component.
+func (s *SharesService) ShareWithOwner(componentType, componentID string, owner *Owner) (*Share, error) {
+	return s.Share(&ShareRequest{
+		ComponentType: componentType,
+		ComponentID:   componentID,
+		ShareToID:     owner.ID,
+		ShareToType:   ShareToTypeUser,
+	})
+}
+
+// ShareWithGroup shares a component with a user group.
+func (s *SharesService) ShareWithGroup(componentType, componentID string, groupID int) (*Share, error) {
+	return s.Share(&ShareRequest{
+		ComponentType: componentType,
+		ComponentID:   componentID,
+		ShareToID:     groupID,
+		ShareToType:   ShareToTypeGroup,
+	})
+}
+
+// ShareWithAll shares a component with all users of the company.
+func (s *SharesService) ShareWithAll(componentType, componentID string) (*Share, error) {
+	return s.Share(&ShareRequest{
+		ComponentType: componentType,
+		ComponentID:   componentID,
+		ShareToType:   ShareToTypeAll,
+	})
+}
+
+// Update replaces the shares of the passed components.
+// Passing a component with an empty list of shares removes all of its shares.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/updateShares
+func (s *SharesService) Update(components *[]ComponentShares) (*[]ComponentShares, error) {
+	reqJSON, err := json.Marshal(components)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data []ComponentShares
+	err = s.client.put("/componentmetadata/shares/updates", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	// the updates may change any share
+	s.client.InvalidateCache("/componentmetadata/shares")
+	return &data, err
+}
+
+// Delete removes a share.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/shares/unshare
+func (s *SharesService) Delete(id int) error {
+	return s.client.delete(fmt.Sprintf("/componentmetadata/shares/%d", id), map[string]string{}, nil, nil)
+}
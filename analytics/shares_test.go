@@ -0,0 +1,286 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestSharesGetAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares"
+
+	raw, err := ioutil.ReadFile("./testdata/Shares.GetAll.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"limit": "10",
+			"page":  "0",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	shares, err := testClient.Shares.GetAll(10, 0)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*shares.Content) != 2 {
+		t.Errorf("Expected %d shares but got %d", 2, len(*shares.Content))
+		return
+	}
+}
+
+func TestSharesGetAllError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/shares", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Shares.GetAll(10, 0)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestSharesGetByID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares/101"
+
+	raw, err := ioutil.ReadFile("./testdata/Shares.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, string(raw))
+	})
+
+	share, err := testClient.Shares.GetByID(101)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if share.ShareID != 101 {
+		t.Errorf("Expected share with ID=101 but got ID=%d", share.ShareID)
+	}
+}
+
+func TestSharesGetByIDError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/shares/101", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := testClient.Shares.GetByID(101)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestSharesGetByComponentIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares/component/search"
+
+	raw, err := ioutil.ReadFile("./testdata/Shares.Search.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(`{"componentType": "segment", "componentIds": ["s300003364_589ce94be4b0c29f29c4f07f"]}`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	components, err := testClient.Shares.GetByComponentIDs(analytics.ComponentTypeSegment, []string{"s300003364_589ce94be4b0c29f29c4f07f"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*(*components)[0].Shares) != 2 {
+		t.Errorf("Expected %d shares but got %d", 2, len(*(*components)[0].Shares))
+	}
+}
+
+func TestSharesGetByComponentIDsError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/shares/component/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Shares.GetByComponentIDs(analytics.ComponentTypeSegment, []string{"s300003364_589ce94be4b0c29f29c4f07f"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestSharesShareWithUser(t *testing.T) {
+	testSharesShare(t, `{"componentType": "segment", "componentId": "s300003364_589ce94be4b0c29f29c4f07f", "shareToId": 711989, "shareToType": "user"}`,
+		func() (*analytics.Share, error) {
+			return testClient.Shares.ShareWithUser(analytics.ComponentTypeSegment, "s300003364_589ce94be4b0c29f29c4f07f", &analytics.User{LoginID: 711989})
+		})
+}
+
+func TestSharesShareWithOwner(t *testing.T) {
+	testSharesShare(t, `{"componentType": "segment", "componentId": "s300003364_589ce94be4b0c29f29c4f07f", "shareToId": 711989, "shareToType": "user"}`,
+		func() (*analytics.Share, error) {
+			return testClient.Shares.ShareWithOwner(analytics.ComponentTypeSegment, "s300003364_589ce94be4b0c29f29c4f07f", &analytics.Owner{ID: 711989})
+		})
+}
+
+func TestSharesShareWithGroup(t *testing.T) {
+	testSharesShare(t, `{"componentType": "segment", "componentId": "s300003364_589ce94be4b0c29f29c4f07f", "shareToId": 42, "shareToType": "group"}`,
+		func() (*analytics.Share, error) {
+			return testClient.Shares.ShareWithGroup(analytics.ComponentTypeSegment, "s300003364_589ce94be4b0c29f29c4f07f", 42)
+		})
+}
+
+func TestSharesShareWithAll(t *testing.T) {
+	testSharesShare(t, `{"componentType": "segment", "componentId": "s300003364_589ce94be4b0c29f29c4f07f", "shareToId": 0, "shareToType": "all"}`,
+		func() (*analytics.Share, error) {
+			return testClient.Shares.ShareWithAll(analytics.ComponentTypeSegment, "s300003364_589ce94be4b0c29f29c4f07f")
+		})
+}
+
+func testSharesShare(t *testing.T, wantBody string, share func() (*analytics.Share, error)) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares"
+
+	raw, err := ioutil.ReadFile("./testdata/Shares.Share.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(wantBody))
+		fmt.Fprint(w, string(raw))
+	})
+
+	s, err := share()
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if s.ShareID != 104 {
+		t.Errorf("Expected share with ID=104 but got ID=%d", s.ShareID)
+	}
+}
+
+func TestSharesShareError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/shares", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := testClient.Shares.ShareWithAll(analytics.ComponentTypeSegment, "s300003364_589ce94be4b0c29f29c4f07f")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestSharesUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares/updates"
+
+	raw, err := ioutil.ReadFile("./testdata/Shares.Updates.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, raw)
+		fmt.Fprint(w, string(raw))
+	})
+
+	components, err := testClient.Shares.Update(&[]analytics.ComponentShares{
+		{
+			ComponentType: analytics.ComponentTypeSegment,
+			ComponentID:   "s300003364_589ce94be4b0c29f29c4f07f",
+			Shares:        &[]analytics.Share{},
+		},
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*(*components)[0].Shares) != 0 {
+		t.Errorf("Expected %d shares but got %d", 0, len(*(*components)[0].Shares))
+	}
+}
+
+func TestSharesDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/shares/101"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.Shares.Delete(101)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestSharesDeleteError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/shares/101", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	err := testClient.Shares.Delete(101)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TagsService handles component tags.
+// Analytics docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags
+type TagsService struct {
+	client *Client
+}
+
+// GetAll returns a list of tags for the current users company.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/getAllTags
+func (s *TagsService) GetAll(limit, page int64) (*Tags, error) {
+	var params = map[string]string{}
+	params["limit"] = strconv.FormatInt(limit, 10)
+	params["page"] = strconv.FormatInt(page, 10)
+
+	var data Tags
+	err := s.client.get("/componentmetadata/tags", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetByID returns a single tag.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/getTag
+func (s *TagsService) GetByID(id string) (*Tag, error) {
+	var data Tag
+	err := s.client.get(fmt.Sprintf("/componentmetadata/tags/%s", id), map[string]string{}, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Create creates the passed tags.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/createTags
+func (s *TagsService) Create(tags *[]Tag) (*[]Tag, error) {
+	reqJSON, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data []Tag
+	err = s.client.create("/componentmetadata/tags", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Delete deletes a tag.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/deleteTag
+func (s *TagsService) Delete(id string) error {
+	err := s.client.delete(fmt.Sprintf("/componentmetadata/tags/%s", id), map[string]string{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	s.invalidateTags()
+	return nil
+}
+
+// GetByComponentIDs returns the tags of the given components.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/getTagsForComponentAndType
+func (s *TagsService) GetByComponentIDs(componentType string, componentIDs []string) (*[]TaggedComponent, error) {
+	var params = map[string]string{}
+	params["componentType"] = componentType
+	params["componentIds"] = strings.Join(componentIDs[:], ",")
+
+	var data []TaggedComponent
+	err := s.client.get("/componentmetadata/tags/search", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetComponentsByTagNames returns the components of the given type tagged with all of the given tag names.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/getComponentIdsForTags
+func (s *TagsService) GetComponentsByTagNames(componentType string, tagNames []string) (*[]TaggedComponent, error) {
+	var params = map[string]string{}
+	params["componentType"] = componentType
+	params["tagNames"] = strings.Join(tagNames[:], ",")
+
+	var data []TaggedComponent
+	err := s.client.get("/componentmetadata/tags/tagnames", params, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// Tag saves the tags of the passed components.
+// The tags of a component are replaced by the passed tags, missing tags are created.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/updateTags
+func (s *TagsService) Tag(components *[]TaggedComponent) (*[]TaggedComponent, error) {
+	reqJSON, err := json.Marshal(components)
+	if err != nil {
+		return nil, err
+	}
+	reqBody := strings.NewReader(string(reqJSON))
+
+	var data []TaggedComponent
+	err = s.client.put("/componentmetadata/tags/tagitems", map[string]string{}, reqBody, &data)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateTags()
+	return &data, err
+}
+
+// Untag removes all tags from the given components.
+// API docs: https://adobedocs.github.io/analytics-2.0-apis/#/tags/removeTagsFromComponent
+func (s *TagsService) Untag(componentType string, componentIDs []string) error {
+	var params = map[string]string{}
+	params["componentType"] = componentType
+	params["componentIds"] = strings.Join(componentIDs[:], ",")
+
+	err := s.client.delete("/componentmetadata/tags", params, nil, nil)
+	if err != nil {
+		return err
+	}
+	s.invalidateTags()
+	return nil
+}
+
+// invalidateTags removes all cached tag responses, the tags of components are also cached by the tag searches.
+func (s *TagsService) invalidateTags() {
+	s.client.InvalidateCache("/componentmetadata/tags")
+}
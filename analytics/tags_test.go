@@ -0,0 +1,322 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+func TestTagsGetAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags"
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.GetAll.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"limit": "10",
+			"page":  "0",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	tags, err := testClient.Tags.GetAll(10, 0)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*tags.Content) != 2 {
+		t.Errorf("Expected %d tags but got %d", 2, len(*tags.Content))
+		return
+	}
+}
+
+func TestTagsGetAllError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Tags.GetAll(10, 0)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestTagsGetByID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags/1234"
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.GetByID.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, string(raw))
+	})
+
+	tag, err := testClient.Tags.GetByID("1234")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if tag.ID != "1234" {
+		t.Errorf("Expected tag with ID=1234 but got ID=%s", tag.ID)
+	}
+
+	if len(*tag.Components) != 1 {
+		t.Errorf("Expected %d tagged components but got %d", 1, len(*tag.Components))
+	}
+}
+
+func TestTagsGetByIDError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/tags/1234", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := testClient.Tags.GetByID("1234")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestTagsCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags"
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.Create.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, []byte(`[{"name": "release", "description": "Release tracking"}]`))
+		fmt.Fprint(w, string(raw))
+	})
+
+	tags, err := testClient.Tags.Create(&[]analytics.Tag{{Name: "release", Description: "Release tracking"}})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*tags) != 1 || (*tags)[0].ID != "1236" {
+		t.Errorf("Expected created tag with ID=1236 but got %v", *tags)
+	}
+}
+
+func TestTagsCreateError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := testClient.Tags.Create(&[]analytics.Tag{{Name: "release"}})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestTagsDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags/1234"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.Tags.Delete("1234")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestTagsDeleteError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/tags/1234", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	err := testClient.Tags.Delete("1234")
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestTagsGetByComponentIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags/search"
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.Search.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"componentType": "segment",
+			"componentIds":  "s300003364_589ce94be4b0c29f29c4f07f,s300003364_5ae7447df118f061698ddc31",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	components, err := testClient.Tags.GetByComponentIDs(analytics.ComponentTypeSegment,
+		[]string{"s300003364_589ce94be4b0c29f29c4f07f", "s300003364_5ae7447df118f061698ddc31"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*components) != 2 {
+		t.Errorf("Expected %d tagged components but got %d", 2, len(*components))
+	}
+}
+
+func TestTagsGetComponentsByTagNames(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags/tagnames"
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.TagNames.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"componentType": "calculatedMetric",
+			"tagNames":      "marketing,release",
+		})
+		fmt.Fprint(w, string(raw))
+	})
+
+	components, err := testClient.Tags.GetComponentsByTagNames(analytics.ComponentTypeCalculatedMetric, []string{"marketing", "release"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(*components) != 1 {
+		t.Errorf("Expected %d tagged components but got %d", 1, len(*components))
+	}
+}
+
+func TestTagsTag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags/tagitems"
+
+	req, err := ioutil.ReadFile("./testdata/Tags.TagItems.Request.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	var components []analytics.TaggedComponent
+	json.Unmarshal(req, &components)
+
+	raw, err := ioutil.ReadFile("./testdata/Tags.TagItems.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestBody(t, r, req)
+		fmt.Fprint(w, string(raw))
+	})
+
+	tagged, err := testClient.Tags.Tag(&components)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if (*(*tagged)[0].Tags)[0].ID != "1234" {
+		t.Errorf("Expected tag with ID=1234 but got ID=%s", (*(*tagged)[0].Tags)[0].ID)
+	}
+}
+
+func TestTagsTagError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc(baseURL+"/componentmetadata/tags/tagitems", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := testClient.Tags.Tag(&[]analytics.TaggedComponent{})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestTagsUntag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	apiEndpoint := baseURL + "/componentmetadata/tags"
+
+	testMux.HandleFunc(apiEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, apiEndpoint)
+		testRequestParams(t, r, map[string]string{
+			"componentType": "dateRange",
+			"componentIds":  "5f3ab2f1c4e5a35d2e8f9a11",
+		})
+		fmt.Fprint(w, `{"result": "success"}`)
+	})
+
+	err := testClient.Tags.Untag(analytics.ComponentTypeDateRange, []string{"5f3ab2f1c4e5a35d2e8f9a11"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
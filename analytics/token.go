@@ -0,0 +1,44 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analytics
+
+// TokenSource provides IMS access tokens.
+// Implementations must be safe for concurrent use, e.g. to share a token source between clients.
+type TokenSource interface {
+	// Token returns a valid access token, renewing it if necessary.
+	Token() (string, error)
+}
+
+// staticTokenSource is a TokenSource that always returns the same access token
+type staticTokenSource string
+
+// StaticTokenSource returns a TokenSource that always returns the passed access token.
+func StaticTokenSource(accessToken string) TokenSource {
+	return staticTokenSource(accessToken)
+}
+
+// Token returns the access token.
+func (s staticTokenSource) Token() (string, error) {
+	return string(s), nil
+}
+
+// newTokenSource returns the token source of the config.
+// The TokenSource takes precedence over the AccessToken, nil is returned if neither is set.
+func newTokenSource(config *Config) TokenSource {
+	if config.TokenSource != nil {
+		return config.TokenSource
+	}
+	if config.AccessToken != "" {
+		return StaticTokenSource(config.AccessToken)
+	}
+	return nil
+}
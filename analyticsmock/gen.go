@@ -0,0 +1,205 @@
+//go:build ignore
+// +build ignore
+
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// gen generates mocks of the service interfaces of the analytics package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	source := flag.String("source", "interfaces.go", "file declaring the interfaces")
+	output := flag.String("output", "mocks.go", "file to write the mocks to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *source, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	header, err := ioutil.ReadFile(*source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	imports := map[string]bool{}
+	var body bytes.Buffer
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			writeMock(&body, typeSpec.Name.Name, iface, imports)
+		}
+	}
+
+	var out bytes.Buffer
+	// keep the license header of the source file
+	out.Write(header[:bytes.Index(header, []byte("*/"))+2])
+	out.WriteString("\n\n// Code generated by gen.go from analytics/interfaces.go; DO NOT EDIT.\n\n")
+	out.WriteString("package analyticsmock\n\nimport (\n")
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		out.WriteString(strconv.Quote(path) + "\n")
+	}
+	out.WriteString("\n\"github.com/adobe/aa-client-go/analytics\"\n)\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeMock writes the mock of an interface.
+func writeMock(w *bytes.Buffer, name string, iface *ast.InterfaceType, imports map[string]bool) {
+	fmt.Fprintf(w, "\n// %s is a mock of analytics.%s.\ntype %s struct {\n\tRecorder\n\n", name, name, name)
+	for _, method := range iface.Methods.List {
+		funcType := method.Type.(*ast.FuncType)
+		methodName := method.Names[0].Name
+		fmt.Fprintf(w, "\t// %sFunc is called by %s.\n\t%sFunc func%s\n", methodName, methodName, methodName, signature(funcType, imports))
+	}
+	fmt.Fprintf(w, "}\n\nvar _ analytics.%s = (*%s)(nil)\n", name, name)
+
+	for _, method := range iface.Methods.List {
+		funcType := method.Type.(*ast.FuncType)
+		methodName := method.Names[0].Name
+		args := strings.Join(paramNames(funcType), ", ")
+		recordArgs := ""
+		if args != "" {
+			recordArgs = ", " + args
+		}
+		ret := "return "
+		if funcType.Results == nil {
+			ret = ""
+		}
+		fmt.Fprintf(w, "\n// %s records the call and calls %sFunc.\n", methodName, methodName)
+		fmt.Fprintf(w, "func (m *%s) %s%s {\n", name, methodName, signature(funcType, imports))
+		fmt.Fprintf(w, "\tm.record(%q%s)\n", methodName, recordArgs)
+		fmt.Fprintf(w, "\tif m.%sFunc == nil {\n\t\tpanic(%q)\n\t}\n", methodName, "analyticsmock: "+name+"."+methodName+"Func is not set")
+		fmt.Fprintf(w, "\t%sm.%sFunc(%s)\n}\n", ret, methodName, args)
+	}
+}
+
+// paramNames returns the parameter names of a function, unnamed parameters are named p0, p1, ...
+func paramNames(funcType *ast.FuncType) []string {
+	var names []string
+	for i, field := range funcType.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, fmt.Sprintf("p%d", i))
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// signature returns the parameters and results of a function with qualified types.
+func signature(funcType *ast.FuncType, imports map[string]bool) string {
+	names := paramNames(funcType)
+	var params []string
+	i := 0
+	for _, field := range funcType.Params.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			params = append(params, names[i]+" "+typeString(field.Type, imports))
+			i++
+		}
+	}
+
+	var results []string
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			results = append(results, typeString(field.Type, imports))
+		}
+	}
+
+	s := "(" + strings.Join(params, ", ") + ")"
+	switch len(results) {
+	case 0:
+	case 1:
+		s += " " + results[0]
+	default:
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return s
+}
+
+// typeString returns a type expression, types of the analytics package are qualified.
+func typeString(expr ast.Expr, imports map[string]bool) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if unicode.IsUpper(rune(t.Name[0])) {
+			return "analytics." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X, imports)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt, imports)
+	case *ast.MapType:
+		return "map[" + typeString(t.Key, imports) + "]" + typeString(t.Value, imports)
+	case *ast.Ellipsis:
+		return "..." + typeString(t.Elt, imports)
+	case *ast.InterfaceType:
+		return "interface{}"
+	case *ast.SelectorExpr:
+		// standard library packages, e.g. context.Context or time.Duration
+		pkg := t.X.(*ast.Ident).Name
+		imports[pkg] = true
+		return pkg + "." + t.Sel.Name
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.RECV:
+			return "<-chan " + typeString(t.Value, imports)
+		case ast.SEND:
+			return "chan<- " + typeString(t.Value, imports)
+		}
+		return "chan " + typeString(t.Value, imports)
+	}
+	log.Fatalf("unsupported type %T", expr)
+	return ""
+}
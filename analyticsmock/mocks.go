@@ -0,0 +1,689 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Code generated by gen.go from analytics/interfaces.go; DO NOT EDIT.
+
+package analyticsmock
+
+import (
+	"context"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+// AnnotationsAPI is a mock of analytics.AnnotationsAPI.
+type AnnotationsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsids string, locale string, filterByIDs string, filterByDateRange *analytics.DateInterval, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.Annotations, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, locale string, expansion []string) (*analytics.Annotation, error)
+	// CreateFunc is called by Create.
+	CreateFunc func(annotation *analytics.Annotation) (*analytics.Annotation, error)
+	// UpdateFunc is called by Update.
+	UpdateFunc func(id string, annotation *analytics.Annotation) (*analytics.Annotation, error)
+	// DeleteFunc is called by Delete.
+	DeleteFunc func(id string) error
+}
+
+var _ analytics.AnnotationsAPI = (*AnnotationsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *AnnotationsAPI) GetAll(rsids string, locale string, filterByIDs string, filterByDateRange *analytics.DateInterval, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.Annotations, error) {
+	m.record("GetAll", rsids, locale, filterByIDs, filterByDateRange, limit, page, sortDirection, sortProperty, expansion, includeType)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: AnnotationsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsids, locale, filterByIDs, filterByDateRange, limit, page, sortDirection, sortProperty, expansion, includeType)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *AnnotationsAPI) GetByID(id string, locale string, expansion []string) (*analytics.Annotation, error) {
+	m.record("GetByID", id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: AnnotationsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, locale, expansion)
+}
+
+// Create records the call and calls CreateFunc.
+func (m *AnnotationsAPI) Create(annotation *analytics.Annotation) (*analytics.Annotation, error) {
+	m.record("Create", annotation)
+	if m.CreateFunc == nil {
+		panic("analyticsmock: AnnotationsAPI.CreateFunc is not set")
+	}
+	return m.CreateFunc(annotation)
+}
+
+// Update records the call and calls UpdateFunc.
+func (m *AnnotationsAPI) Update(id string, annotation *analytics.Annotation) (*analytics.Annotation, error) {
+	m.record("Update", id, annotation)
+	if m.UpdateFunc == nil {
+		panic("analyticsmock: AnnotationsAPI.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(id, annotation)
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *AnnotationsAPI) Delete(id string) error {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		panic("analyticsmock: AnnotationsAPI.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(id)
+}
+
+// CalculatedMetricsAPI is a mock of analytics.CalculatedMetricsAPI.
+type CalculatedMetricsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsids string, ownerID string, filterByIds string, toBeUsedInRsid string, locale string, name string, tagNames string, favorite bool, approved bool, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.CalculatedMetrics, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, locale string, expansion []string) (*analytics.CalculatedMetric, error)
+}
+
+var _ analytics.CalculatedMetricsAPI = (*CalculatedMetricsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *CalculatedMetricsAPI) GetAll(rsids string, ownerID string, filterByIds string, toBeUsedInRsid string, locale string, name string, tagNames string, favorite bool, approved bool, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.CalculatedMetrics, error) {
+	m.record("GetAll", rsids, ownerID, filterByIds, toBeUsedInRsid, locale, name, tagNames, favorite, approved, limit, page, sortDirection, sortProperty, expansion, includeType)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: CalculatedMetricsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsids, ownerID, filterByIds, toBeUsedInRsid, locale, name, tagNames, favorite, approved, limit, page, sortDirection, sortProperty, expansion, includeType)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *CalculatedMetricsAPI) GetByID(id string, locale string, expansion []string) (*analytics.CalculatedMetric, error) {
+	m.record("GetByID", id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: CalculatedMetricsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, locale, expansion)
+}
+
+// CollectionsAPI is a mock of analytics.CollectionsAPI.
+type CollectionsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsids string, rsidContains string, limit int64, page int64, expansion []string) (*analytics.Collections, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, expansion []string) (*analytics.Collection, error)
+}
+
+var _ analytics.CollectionsAPI = (*CollectionsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *CollectionsAPI) GetAll(rsids string, rsidContains string, limit int64, page int64, expansion []string) (*analytics.Collections, error) {
+	m.record("GetAll", rsids, rsidContains, limit, page, expansion)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: CollectionsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsids, rsidContains, limit, page, expansion)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *CollectionsAPI) GetByID(id string, expansion []string) (*analytics.Collection, error) {
+	m.record("GetByID", id, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: CollectionsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, expansion)
+}
+
+// DateRangesAPI is a mock of analytics.DateRangesAPI.
+type DateRangesAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(locale string, filterByIDs string, limit int64, page int64, expansion []string, includeType []string) (*analytics.DateRanges, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, locale string, expansion []string) (*analytics.DateRange, error)
+	// CreateFunc is called by Create.
+	CreateFunc func(dateRange *analytics.DateRange) (*analytics.DateRange, error)
+	// UpdateFunc is called by Update.
+	UpdateFunc func(id string, dateRange *analytics.DateRange) (*analytics.DateRange, error)
+	// DeleteFunc is called by Delete.
+	DeleteFunc func(id string) error
+}
+
+var _ analytics.DateRangesAPI = (*DateRangesAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *DateRangesAPI) GetAll(locale string, filterByIDs string, limit int64, page int64, expansion []string, includeType []string) (*analytics.DateRanges, error) {
+	m.record("GetAll", locale, filterByIDs, limit, page, expansion, includeType)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: DateRangesAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(locale, filterByIDs, limit, page, expansion, includeType)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *DateRangesAPI) GetByID(id string, locale string, expansion []string) (*analytics.DateRange, error) {
+	m.record("GetByID", id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: DateRangesAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, locale, expansion)
+}
+
+// Create records the call and calls CreateFunc.
+func (m *DateRangesAPI) Create(dateRange *analytics.DateRange) (*analytics.DateRange, error) {
+	m.record("Create", dateRange)
+	if m.CreateFunc == nil {
+		panic("analyticsmock: DateRangesAPI.CreateFunc is not set")
+	}
+	return m.CreateFunc(dateRange)
+}
+
+// Update records the call and calls UpdateFunc.
+func (m *DateRangesAPI) Update(id string, dateRange *analytics.DateRange) (*analytics.DateRange, error) {
+	m.record("Update", id, dateRange)
+	if m.UpdateFunc == nil {
+		panic("analyticsmock: DateRangesAPI.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(id, dateRange)
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *DateRangesAPI) Delete(id string) error {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		panic("analyticsmock: DateRangesAPI.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(id)
+}
+
+// DimensionsAPI is a mock of analytics.DimensionsAPI.
+type DimensionsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsID string, locale string, segmentable bool, reportable bool, classifiable bool, expansion []string) (*[]analytics.Dimension, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(rsID string, id string, locale string, expansion []string) (*analytics.Dimension, error)
+}
+
+var _ analytics.DimensionsAPI = (*DimensionsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *DimensionsAPI) GetAll(rsID string, locale string, segmentable bool, reportable bool, classifiable bool, expansion []string) (*[]analytics.Dimension, error) {
+	m.record("GetAll", rsID, locale, segmentable, reportable, classifiable, expansion)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: DimensionsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsID, locale, segmentable, reportable, classifiable, expansion)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *DimensionsAPI) GetByID(rsID string, id string, locale string, expansion []string) (*analytics.Dimension, error) {
+	m.record("GetByID", rsID, id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: DimensionsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(rsID, id, locale, expansion)
+}
+
+// MetricsAPI is a mock of analytics.MetricsAPI.
+type MetricsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsID string, locale string, segmentable bool, expansion []string) (*[]analytics.Metric, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(rsID string, id string, locale string, expansion []string) (*analytics.Metric, error)
+}
+
+var _ analytics.MetricsAPI = (*MetricsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *MetricsAPI) GetAll(rsID string, locale string, segmentable bool, expansion []string) (*[]analytics.Metric, error) {
+	m.record("GetAll", rsID, locale, segmentable, expansion)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: MetricsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsID, locale, segmentable, expansion)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *MetricsAPI) GetByID(rsID string, id string, locale string, expansion []string) (*analytics.Metric, error) {
+	m.record("GetByID", rsID, id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: MetricsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(rsID, id, locale, expansion)
+}
+
+// ProjectsAPI is a mock of analytics.ProjectsAPI.
+type ProjectsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(locale string, filterByIDs string, limit int64, page int64, expansion []string, includeType []string) (*analytics.Projects, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, locale string, expansion []string) (*analytics.Project, error)
+	// CreateFunc is called by Create.
+	CreateFunc func(project *analytics.Project) (*analytics.Project, error)
+	// UpdateFunc is called by Update.
+	UpdateFunc func(id string, project *analytics.Project) (*analytics.Project, error)
+	// DeleteFunc is called by Delete.
+	DeleteFunc func(id string) error
+}
+
+var _ analytics.ProjectsAPI = (*ProjectsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *ProjectsAPI) GetAll(locale string, filterByIDs string, limit int64, page int64, expansion []string, includeType []string) (*analytics.Projects, error) {
+	m.record("GetAll", locale, filterByIDs, limit, page, expansion, includeType)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: ProjectsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(locale, filterByIDs, limit, page, expansion, includeType)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *ProjectsAPI) GetByID(id string, locale string, expansion []string) (*analytics.Project, error) {
+	m.record("GetByID", id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: ProjectsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, locale, expansion)
+}
+
+// Create records the call and calls CreateFunc.
+func (m *ProjectsAPI) Create(project *analytics.Project) (*analytics.Project, error) {
+	m.record("Create", project)
+	if m.CreateFunc == nil {
+		panic("analyticsmock: ProjectsAPI.CreateFunc is not set")
+	}
+	return m.CreateFunc(project)
+}
+
+// Update records the call and calls UpdateFunc.
+func (m *ProjectsAPI) Update(id string, project *analytics.Project) (*analytics.Project, error) {
+	m.record("Update", id, project)
+	if m.UpdateFunc == nil {
+		panic("analyticsmock: ProjectsAPI.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(id, project)
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *ProjectsAPI) Delete(id string) error {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		panic("analyticsmock: ProjectsAPI.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(id)
+}
+
+// ReportsAPI is a mock of analytics.ReportsAPI.
+type ReportsAPI struct {
+	Recorder
+
+	// RunFunc is called by Run.
+	RunFunc func(rankedRequest *analytics.RankedRequest) (*analytics.RankedReportData, error)
+	// TopItemsFunc is called by TopItems.
+	TopItemsFunc func(rsid string, dimension string, search string, dateRange string, limit int64, page int64) (*analytics.TopItemsData, error)
+	// RunRealtimeFunc is called by RunRealtime.
+	RunRealtimeFunc func(realtimeRequest *analytics.RealtimeRequest) (*analytics.RealtimeReportData, error)
+	// PollRealtimeFunc is called by PollRealtime.
+	PollRealtimeFunc func(ctx context.Context, realtimeRequest *analytics.RealtimeRequest, interval time.Duration) (<-chan analytics.RealtimeBucket, <-chan error)
+	// RunFreeformTableFunc is called by RunFreeformTable.
+	RunFreeformTableFunc func(table *analytics.FreeformTable) (*analytics.FreeformTableData, error)
+	// RunComparisonFunc is called by RunComparison.
+	RunComparisonFunc func(rankedRequest *analytics.RankedRequest, current string, previous string) (*analytics.ComparisonReport, error)
+	// RunPercentChangeFunc is called by RunPercentChange.
+	RunPercentChangeFunc func(rankedRequest *analytics.RankedRequest) (*analytics.ComparisonReport, error)
+}
+
+var _ analytics.ReportsAPI = (*ReportsAPI)(nil)
+
+// Run records the call and calls RunFunc.
+func (m *ReportsAPI) Run(rankedRequest *analytics.RankedRequest) (*analytics.RankedReportData, error) {
+	m.record("Run", rankedRequest)
+	if m.RunFunc == nil {
+		panic("analyticsmock: ReportsAPI.RunFunc is not set")
+	}
+	return m.RunFunc(rankedRequest)
+}
+
+// TopItems records the call and calls TopItemsFunc.
+func (m *ReportsAPI) TopItems(rsid string, dimension string, search string, dateRange string, limit int64, page int64) (*analytics.TopItemsData, error) {
+	m.record("TopItems", rsid, dimension, search, dateRange, limit, page)
+	if m.TopItemsFunc == nil {
+		panic("analyticsmock: ReportsAPI.TopItemsFunc is not set")
+	}
+	return m.TopItemsFunc(rsid, dimension, search, dateRange, limit, page)
+}
+
+// RunRealtime records the call and calls RunRealtimeFunc.
+func (m *ReportsAPI) RunRealtime(realtimeRequest *analytics.RealtimeRequest) (*analytics.RealtimeReportData, error) {
+	m.record("RunRealtime", realtimeRequest)
+	if m.RunRealtimeFunc == nil {
+		panic("analyticsmock: ReportsAPI.RunRealtimeFunc is not set")
+	}
+	return m.RunRealtimeFunc(realtimeRequest)
+}
+
+// PollRealtime records the call and calls PollRealtimeFunc.
+func (m *ReportsAPI) PollRealtime(ctx context.Context, realtimeRequest *analytics.RealtimeRequest, interval time.Duration) (<-chan analytics.RealtimeBucket, <-chan error) {
+	m.record("PollRealtime", ctx, realtimeRequest, interval)
+	if m.PollRealtimeFunc == nil {
+		panic("analyticsmock: ReportsAPI.PollRealtimeFunc is not set")
+	}
+	return m.PollRealtimeFunc(ctx, realtimeRequest, interval)
+}
+
+// RunFreeformTable records the call and calls RunFreeformTableFunc.
+func (m *ReportsAPI) RunFreeformTable(table *analytics.FreeformTable) (*analytics.FreeformTableData, error) {
+	m.record("RunFreeformTable", table)
+	if m.RunFreeformTableFunc == nil {
+		panic("analyticsmock: ReportsAPI.RunFreeformTableFunc is not set")
+	}
+	return m.RunFreeformTableFunc(table)
+}
+
+// RunComparison records the call and calls RunComparisonFunc.
+func (m *ReportsAPI) RunComparison(rankedRequest *analytics.RankedRequest, current string, previous string) (*analytics.ComparisonReport, error) {
+	m.record("RunComparison", rankedRequest, current, previous)
+	if m.RunComparisonFunc == nil {
+		panic("analyticsmock: ReportsAPI.RunComparisonFunc is not set")
+	}
+	return m.RunComparisonFunc(rankedRequest, current, previous)
+}
+
+// RunPercentChange records the call and calls RunPercentChangeFunc.
+func (m *ReportsAPI) RunPercentChange(rankedRequest *analytics.RankedRequest) (*analytics.ComparisonReport, error) {
+	m.record("RunPercentChange", rankedRequest)
+	if m.RunPercentChangeFunc == nil {
+		panic("analyticsmock: ReportsAPI.RunPercentChangeFunc is not set")
+	}
+	return m.RunPercentChangeFunc(rankedRequest)
+}
+
+// SegmentsAPI is a mock of analytics.SegmentsAPI.
+type SegmentsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(rsids string, segmentFilter string, locale string, name string, tagNames string, filterByPublishedSegments string, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.Segments, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string, locale string, expansion []string) (*analytics.Segment, error)
+}
+
+var _ analytics.SegmentsAPI = (*SegmentsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *SegmentsAPI) GetAll(rsids string, segmentFilter string, locale string, name string, tagNames string, filterByPublishedSegments string, limit int64, page int64, sortDirection string, sortProperty string, expansion []string, includeType []string) (*analytics.Segments, error) {
+	m.record("GetAll", rsids, segmentFilter, locale, name, tagNames, filterByPublishedSegments, limit, page, sortDirection, sortProperty, expansion, includeType)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: SegmentsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(rsids, segmentFilter, locale, name, tagNames, filterByPublishedSegments, limit, page, sortDirection, sortProperty, expansion, includeType)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *SegmentsAPI) GetByID(id string, locale string, expansion []string) (*analytics.Segment, error) {
+	m.record("GetByID", id, locale, expansion)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: SegmentsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id, locale, expansion)
+}
+
+// SharesAPI is a mock of analytics.SharesAPI.
+type SharesAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(limit int64, page int64) (*analytics.Shares, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id int) (*analytics.Share, error)
+	// GetByComponentIDsFunc is called by GetByComponentIDs.
+	GetByComponentIDsFunc func(componentType string, componentIDs []string) (*[]analytics.ComponentShares, error)
+	// ShareFunc is called by Share.
+	ShareFunc func(shareRequest *analytics.ShareRequest) (*analytics.Share, error)
+	// ShareWithUserFunc is called by ShareWithUser.
+	ShareWithUserFunc func(componentType string, componentID string, user *analytics.User) (*analytics.Share, error)
+	// ShareWithOwnerFunc is called by ShareWithOwner.
+	ShareWithOwnerFunc func(componentType string, componentID string, owner *analytics.Owner) (*analytics.Share, error)
+	// ShareWithGroupFunc is called by ShareWithGroup.
+	ShareWithGroupFunc func(componentType string, componentID string, groupID int) (*analytics.Share, error)
+	// ShareWithAllFunc is called by ShareWithAll.
+	ShareWithAllFunc func(componentType string, componentID string) (*analytics.Share, error)
+	// UpdateFunc is called by Update.
+	UpdateFunc func(components *[]analytics.ComponentShares) (*[]analytics.ComponentShares, error)
+	// DeleteFunc is called by Delete.
+	DeleteFunc func(id int) error
+}
+
+var _ analytics.SharesAPI = (*SharesAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *SharesAPI) GetAll(limit int64, page int64) (*analytics.Shares, error) {
+	m.record("GetAll", limit, page)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: SharesAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(limit, page)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *SharesAPI) GetByID(id int) (*analytics.Share, error) {
+	m.record("GetByID", id)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: SharesAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id)
+}
+
+// GetByComponentIDs records the call and calls GetByComponentIDsFunc.
+func (m *SharesAPI) GetByComponentIDs(componentType string, componentIDs []string) (*[]analytics.ComponentShares, error) {
+	m.record("GetByComponentIDs", componentType, componentIDs)
+	if m.GetByComponentIDsFunc == nil {
+		panic("analyticsmock: SharesAPI.GetByComponentIDsFunc is not set")
+	}
+	return m.GetByComponentIDsFunc(componentType, componentIDs)
+}
+
+// Share records the call and calls ShareFunc.
+func (m *SharesAPI) Share(shareRequest *analytics.ShareRequest) (*analytics.Share, error) {
+	m.record("Share", shareRequest)
+	if m.ShareFunc == nil {
+		panic("analyticsmock: SharesAPI.ShareFunc is not set")
+	}
+	return m.ShareFunc(shareRequest)
+}
+
+// ShareWithUser records the call and calls ShareWithUserFunc.
+func (m *SharesAPI) ShareWithUser(componentType string, componentID string, user *analytics.User) (*analytics.Share, error) {
+	m.record("ShareWithUser", componentType, componentID, user)
+	if m.ShareWithUserFunc == nil {
+		panic("analyticsmock: SharesAPI.ShareWithUserFunc is not set")
+	}
+	return m.ShareWithUserFunc(componentType, componentID, user)
+}
+
+// ShareWithOwner records the call and calls ShareWithOwnerFunc.
+func (m *SharesAPI) ShareWithOwner(componentType string, componentID string, owner *analytics.Owner) (*analytics.Share, error) {
+	m.record("ShareWithOwner", componentType, componentID, owner)
+	if m.ShareWithOwnerFunc == nil {
+		panic("analyticsmock: SharesAPI.ShareWithOwnerFunc is not set")
+	}
+	return m.ShareWithOwnerFunc(componentType, componentID, owner)
+}
+
+// ShareWithGroup records the call and calls ShareWithGroupFunc.
+func (m *SharesAPI) ShareWithGroup(componentType string, componentID string, groupID int) (*analytics.Share, error) {
+	m.record("ShareWithGroup", componentType, componentID, groupID)
+	if m.ShareWithGroupFunc == nil {
+		panic("analyticsmock: SharesAPI.ShareWithGroupFunc is not set")
+	}
+	return m.ShareWithGroupFunc(componentType, componentID, groupID)
+}
+
+// ShareWithAll records the call and calls ShareWithAllFunc.
+func (m *SharesAPI) ShareWithAll(componentType string, componentID string) (*analytics.Share, error) {
+	m.record("ShareWithAll", componentType, componentID)
+	if m.ShareWithAllFunc == nil {
+		panic("analyticsmock: SharesAPI.ShareWithAllFunc is not set")
+	}
+	return m.ShareWithAllFunc(componentType, componentID)
+}
+
+// Update records the call and calls UpdateFunc.
+func (m *SharesAPI) Update(components *[]analytics.ComponentShares) (*[]analytics.ComponentShares, error) {
+	m.record("Update", components)
+	if m.UpdateFunc == nil {
+		panic("analyticsmock: SharesAPI.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(components)
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *SharesAPI) Delete(id int) error {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		panic("analyticsmock: SharesAPI.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(id)
+}
+
+// TagsAPI is a mock of analytics.TagsAPI.
+type TagsAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(limit int64, page int64) (*analytics.Tags, error)
+	// GetByIDFunc is called by GetByID.
+	GetByIDFunc func(id string) (*analytics.Tag, error)
+	// CreateFunc is called by Create.
+	CreateFunc func(tags *[]analytics.Tag) (*[]analytics.Tag, error)
+	// DeleteFunc is called by Delete.
+	DeleteFunc func(id string) error
+	// GetByComponentIDsFunc is called by GetByComponentIDs.
+	GetByComponentIDsFunc func(componentType string, componentIDs []string) (*[]analytics.TaggedComponent, error)
+	// GetComponentsByTagNamesFunc is called by GetComponentsByTagNames.
+	GetComponentsByTagNamesFunc func(componentType string, tagNames []string) (*[]analytics.TaggedComponent, error)
+	// TagFunc is called by Tag.
+	TagFunc func(components *[]analytics.TaggedComponent) (*[]analytics.TaggedComponent, error)
+	// UntagFunc is called by Untag.
+	UntagFunc func(componentType string, componentIDs []string) error
+}
+
+var _ analytics.TagsAPI = (*TagsAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *TagsAPI) GetAll(limit int64, page int64) (*analytics.Tags, error) {
+	m.record("GetAll", limit, page)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: TagsAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(limit, page)
+}
+
+// GetByID records the call and calls GetByIDFunc.
+func (m *TagsAPI) GetByID(id string) (*analytics.Tag, error) {
+	m.record("GetByID", id)
+	if m.GetByIDFunc == nil {
+		panic("analyticsmock: TagsAPI.GetByIDFunc is not set")
+	}
+	return m.GetByIDFunc(id)
+}
+
+// Create records the call and calls CreateFunc.
+func (m *TagsAPI) Create(tags *[]analytics.Tag) (*[]analytics.Tag, error) {
+	m.record("Create", tags)
+	if m.CreateFunc == nil {
+		panic("analyticsmock: TagsAPI.CreateFunc is not set")
+	}
+	return m.CreateFunc(tags)
+}
+
+// Delete records the call and calls DeleteFunc.
+func (m *TagsAPI) Delete(id string) error {
+	m.record("Delete", id)
+	if m.DeleteFunc == nil {
+		panic("analyticsmock: TagsAPI.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(id)
+}
+
+// GetByComponentIDs records the call and calls GetByComponentIDsFunc.
+func (m *TagsAPI) GetByComponentIDs(componentType string, componentIDs []string) (*[]analytics.TaggedComponent, error) {
+	m.record("GetByComponentIDs", componentType, componentIDs)
+	if m.GetByComponentIDsFunc == nil {
+		panic("analyticsmock: TagsAPI.GetByComponentIDsFunc is not set")
+	}
+	return m.GetByComponentIDsFunc(componentType, componentIDs)
+}
+
+// GetComponentsByTagNames records the call and calls GetComponentsByTagNamesFunc.
+func (m *TagsAPI) GetComponentsByTagNames(componentType string, tagNames []string) (*[]analytics.TaggedComponent, error) {
+	m.record("GetComponentsByTagNames", componentType, tagNames)
+	if m.GetComponentsByTagNamesFunc == nil {
+		panic("analyticsmock: TagsAPI.GetComponentsByTagNamesFunc is not set")
+	}
+	return m.GetComponentsByTagNamesFunc(componentType, tagNames)
+}
+
+// Tag records the call and calls TagFunc.
+func (m *TagsAPI) Tag(components *[]analytics.TaggedComponent) (*[]analytics.TaggedComponent, error) {
+	m.record("Tag", components)
+	if m.TagFunc == nil {
+		panic("analyticsmock: TagsAPI.TagFunc is not set")
+	}
+	return m.TagFunc(components)
+}
+
+// Untag records the call and calls UntagFunc.
+func (m *TagsAPI) Untag(componentType string, componentIDs []string) error {
+	m.record("Untag", componentType, componentIDs)
+	if m.UntagFunc == nil {
+		panic("analyticsmock: TagsAPI.UntagFunc is not set")
+	}
+	return m.UntagFunc(componentType, componentIDs)
+}
+
+// UsersAPI is a mock of analytics.UsersAPI.
+type UsersAPI struct {
+	Recorder
+
+	// GetAllFunc is called by GetAll.
+	GetAllFunc func(limit int64, page int64) (*analytics.Users, error)
+	// GetCurrentFunc is called by GetCurrent.
+	GetCurrentFunc func() (*analytics.User, error)
+}
+
+var _ analytics.UsersAPI = (*UsersAPI)(nil)
+
+// GetAll records the call and calls GetAllFunc.
+func (m *UsersAPI) GetAll(limit int64, page int64) (*analytics.Users, error) {
+	m.record("GetAll", limit, page)
+	if m.GetAllFunc == nil {
+		panic("analyticsmock: UsersAPI.GetAllFunc is not set")
+	}
+	return m.GetAllFunc(limit, page)
+}
+
+// GetCurrent records the call and calls GetCurrentFunc.
+func (m *UsersAPI) GetCurrent() (*analytics.User, error) {
+	m.record("GetCurrent")
+	if m.GetCurrentFunc == nil {
+		panic("analyticsmock: UsersAPI.GetCurrentFunc is not set")
+	}
+	return m.GetCurrentFunc()
+}
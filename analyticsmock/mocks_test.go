@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analyticsmock_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/analyticsmock"
+)
+
+// segmentNames is an example of code depending on a service interface
+func segmentNames(segments analytics.SegmentsAPI, ids []string) ([]string, error) {
+	var names []string
+	for _, id := range ids {
+		segment, err := segments.GetByID(id, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, segment.Name)
+	}
+	return names, nil
+}
+
+func TestSegmentsAPI(t *testing.T) {
+	segments := &analyticsmock.SegmentsAPI{}
+	segments.GetByIDFunc = func(id, locale string, expansion []string) (*analytics.Segment, error) {
+		if id == "missing" {
+			return nil, fmt.Errorf("not found")
+		}
+		return &analytics.Segment{ID: id, Name: "Segment " + id}, nil
+	}
+
+	names, err := segmentNames(segments, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(names) != 2 || names[1] != "Segment 2" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	_, err = segmentNames(segments, []string{"missing"})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+
+	if segments.CallCount("GetByID") != 3 {
+		t.Errorf("Expected %d calls but got %d", 3, segments.CallCount("GetByID"))
+	}
+	calls := segments.Calls()
+	if calls[1].Method != "GetByID" || calls[1].Args[0] != "2" {
+		t.Errorf("Unexpected call %+v", calls[1])
+	}
+}
+
+func TestMockNotSet(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic but got none")
+		}
+	}()
+
+	var reports analytics.ReportsAPI = &analyticsmock.ReportsAPI{}
+	reports.Run(&analytics.RankedRequest{})
+}
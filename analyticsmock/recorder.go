@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package analyticsmock provides mocks of the service interfaces of the analytics package.
+// The mocks are generated from analytics/interfaces.go, run "go generate ./analytics" to update them.
+//
+// A mock method calls the function field of the same name with the suffix Func and panics if it is not set:
+//
+//	segments := &analyticsmock.SegmentsAPI{}
+//	segments.GetByIDFunc = func(id, locale string, expansion []string) (*analytics.Segment, error) {
+//		return &analytics.Segment{ID: id}, nil
+//	}
+package analyticsmock
+
+import "sync"
+
+// Call represents a call of a mock method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls of a mock, it is embedded in all mocks.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the recorded calls.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+// CallCount returns the number of recorded calls of the method.
+func (r *Recorder) CallCount(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, call := range r.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// record records a call.
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
@@ -0,0 +1,451 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analyticstest
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+// Metrics computed by a ReportEngine, other metrics are the sum of the hit events of the same ID
+const (
+	MetricVisits      = "metrics/visits"
+	MetricVisitors    = "metrics/visitors"
+	MetricPageViews   = "metrics/pageviews"
+	MetricOccurrences = "metrics/occurrences"
+)
+
+// DimensionDay is the day dimension, its values are derived from the hit timestamps
+const DimensionDay = "variables/daterangeday"
+
+// Segment contexts of a ReportEngine segment
+const (
+	SegmentContextHits     = "hits"
+	SegmentContextVisits   = "visits"
+	SegmentContextVisitors = "visitors"
+)
+
+// Hit represents a synthetic hit
+type Hit struct {
+	VisitorID string
+	VisitID   string
+	Timestamp time.Time
+	// PageView marks page view hits, e.g. of s.t() calls
+	PageView bool
+	// Dimensions holds the dimension values, e.g. "variables/page": "Home"
+	Dimensions map[string]string
+	// Events holds the event values, e.g. "metrics/orders": 1
+	Events map[string]float64
+}
+
+// engineSegment represents a segment of a ReportEngine
+type engineSegment struct {
+	context   string
+	predicate func(hit *Hit) bool
+}
+
+// ReportEngine answers ranked report requests locally from hits, e.g. to test code consuming reports.
+// It supports ranked dimensions, the metrics visits, visitors, page views, occurrences and events,
+// date range, segment and breakdown filters, metric filters, item ID and CONTAINS searches, sorting and pagination.
+// It is safe for concurrent use, hits and segments can be added while a Server runs reports.
+type ReportEngine struct {
+	mu       sync.RWMutex
+	hits     []Hit
+	segments map[string]*engineSegment
+}
+
+// NewReportEngine returns a new ReportEngine with the hits.
+func NewReportEngine(hits ...Hit) *ReportEngine {
+	return &ReportEngine{
+		hits:     append([]Hit{}, hits...),
+		segments: map[string]*engineSegment{},
+	}
+}
+
+// AddHits adds hits.
+func (e *ReportEngine) AddHits(hits ...Hit) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hits = append(e.hits, hits...)
+}
+
+// AddSegment adds a segment which can be referenced by ID in filters.
+// The predicate selects hits, the context extends the selection to the visits or visitors of the hits.
+func (e *ReportEngine) AddSegment(id, context string, predicate func(hit *Hit) bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.segments[id] = &engineSegment{context: context, predicate: predicate}
+}
+
+// engineRow represents a row of a report
+type engineRow struct {
+	itemID string
+	value  string
+	data   []float64
+}
+
+// Run runs a ranked report.
+func (e *ReportEngine) Run(rankedRequest *analytics.RankedRequest) (*analytics.RankedReportData, error) {
+	if rankedRequest == nil || rankedRequest.Dimension == "" {
+		return nil, fmt.Errorf("missing dimension")
+	}
+	if rankedRequest.MetricContainer == nil || rankedRequest.MetricContainer.Metrics == nil || len(*rankedRequest.MetricContainer.Metrics) == 0 {
+		return nil, fmt.Errorf("missing metrics")
+	}
+	dimension := rankedRequest.Dimension
+	metrics := *rankedRequest.MetricContainer.Metrics
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	hits := make([]*Hit, len(e.hits))
+	for i := range e.hits {
+		hits[i] = &e.hits[i]
+	}
+
+	var err error
+	if rankedRequest.GlobalFilters != nil {
+		for _, filter := range *rankedRequest.GlobalFilters {
+			hits, err = e.filter(hits, filter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	metricFilters := map[string]analytics.RankedRequestReportFilter{}
+	if rankedRequest.MetricContainer.MetricFilters != nil {
+		for _, filter := range *rankedRequest.MetricContainer.MetricFilters {
+			metricFilters[filter.ID] = filter
+		}
+	}
+
+	// hits of each column, after applying the metric filters
+	columnHits := make([][]*Hit, len(metrics))
+	columnIDs := make([]string, len(metrics))
+	for i, metric := range metrics {
+		columnIDs[i] = metric.ColumnID
+		if columnIDs[i] == "" {
+			columnIDs[i] = strconv.Itoa(i)
+		}
+		columnHits[i] = hits
+		for _, filterID := range metric.Filters {
+			filter, ok := metricFilters[filterID]
+			if !ok {
+				return nil, fmt.Errorf("unknown metric filter %q", filterID)
+			}
+			columnHits[i], err = e.filter(columnHits[i], filter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	returnNones := rankedRequest.Settings != nil && rankedRequest.Settings.NonesBehavior == "return-nones"
+	search, err := newEngineSearch(rankedRequest.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows of the items of the global hits
+	rows := map[string]*engineRow{}
+	var order []string
+	for _, hit := range hits {
+		itemID, value, ok := dimensionItem(hit, dimension, returnNones)
+		if !ok || !search.matches(itemID, value) {
+			continue
+		}
+		if _, ok := rows[itemID]; !ok {
+			rows[itemID] = &engineRow{itemID: itemID, value: value, data: make([]float64, len(metrics))}
+			order = append(order, itemID)
+		}
+	}
+
+	totals := make([]int, len(metrics))
+	filteredTotals := make([]int, len(metrics))
+	for i, metric := range metrics {
+		items := map[string][]*Hit{}
+		var filtered []*Hit
+		for _, hit := range columnHits[i] {
+			itemID, _, ok := dimensionItem(hit, dimension, returnNones)
+			if ok && rows[itemID] != nil {
+				items[itemID] = append(items[itemID], hit)
+				filtered = append(filtered, hit)
+			}
+		}
+		for itemID, itemHits := range items {
+			rows[itemID].data[i] = metricValue(metric.ID, itemHits)
+		}
+		totals[i] = int(math.Round(metricValue(metric.ID, columnHits[i])))
+		filteredTotals[i] = int(math.Round(metricValue(metric.ID, filtered)))
+	}
+
+	sorted := make([]*engineRow, 0, len(order))
+	for _, itemID := range order {
+		sorted = append(sorted, rows[itemID])
+	}
+	sortRows(sorted, metrics, rankedRequest.Settings)
+
+	limit, number := 50, 0
+	if rankedRequest.Settings != nil {
+		if rankedRequest.Settings.Limit > 0 {
+			limit = rankedRequest.Settings.Limit
+		}
+		number = rankedRequest.Settings.Page
+	}
+	start, end := pageBounds(len(sorted), limit, number)
+
+	reportRows := []analytics.RankedReportRowData{}
+	for _, row := range sorted[start:end] {
+		data := make([]float32, len(row.data))
+		for i, value := range row.data {
+			data[i] = float32(value)
+		}
+		reportRows = append(reportRows, analytics.RankedReportRowData{ItemID: row.itemID, Value: row.value, Data: data})
+	}
+
+	pages := totalPages(len(sorted), limit)
+	return &analytics.RankedReportData{
+		TotalPages:       pages,
+		FirstPage:        number == 0,
+		LastPage:         number >= pages-1,
+		NumberOfElements: len(reportRows),
+		Number:           number,
+		TotalElements:    len(sorted),
+		Columns: &analytics.RankedReportColumnMetaData{
+			Dimension: &analytics.RankedReportDimension{ID: dimension, Type: "string"},
+			ColumnIDs: columnIDs,
+		},
+		Rows: &reportRows,
+		SummaryData: &analytics.RankedReportSummaryData{
+			RankedReportSummaryDataTotals: &analytics.RankedReportSummaryDataTotals{
+				FilteredTotals: filteredTotals,
+				Totals:         totals,
+			},
+		},
+	}, nil
+}
+
+// filter returns the hits matching a date range, segment or breakdown filter.
+func (e *ReportEngine) filter(hits []*Hit, filter analytics.RankedRequestReportFilter) ([]*Hit, error) {
+	var matches func(hit *Hit) bool
+	switch filter.Type {
+	case "dateRange":
+		interval, err := analytics.ParseDateInterval(filter.DateRange)
+		if err != nil {
+			return nil, err
+		}
+		matches = func(hit *Hit) bool {
+			// date ranges are in the report suite time zone, compare the wall clock
+			t := time.Date(hit.Timestamp.Year(), hit.Timestamp.Month(), hit.Timestamp.Day(),
+				hit.Timestamp.Hour(), hit.Timestamp.Minute(), hit.Timestamp.Second(), hit.Timestamp.Nanosecond(), time.UTC)
+			// the end is inclusive like in the API, e.g. "2020-04-01T00:00:00.000/2020-04-07T23:59:59.999"
+			t = t.Truncate(time.Millisecond)
+			return !t.Before(interval.Start) && !t.After(interval.End)
+		}
+	case "segment":
+		segment, ok := e.segments[filter.SegmentID]
+		if !ok {
+			return nil, fmt.Errorf("unknown segment %q", filter.SegmentID)
+		}
+		matches = segment.matcher(hits)
+	case "breakdown":
+		itemIDs := map[string]bool{filter.ItemID: true}
+		for _, itemID := range filter.ItemIDs {
+			itemIDs[itemID] = true
+		}
+		matches = func(hit *Hit) bool {
+			itemID, _, ok := dimensionItem(hit, filter.Dimension, true)
+			return ok && itemIDs[itemID]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported filter type %q", filter.Type)
+	}
+
+	var filtered []*Hit
+	for _, hit := range hits {
+		if matches(hit) {
+			filtered = append(filtered, hit)
+		}
+	}
+	return filtered, nil
+}
+
+// matcher returns a function matching the hits of the segment, visits and visitors are selected from hits.
+func (s *engineSegment) matcher(hits []*Hit) func(hit *Hit) bool {
+	key := func(hit *Hit) string {
+		return hit.VisitorID + "\x00" + hit.VisitID
+	}
+	switch s.context {
+	case SegmentContextVisitors:
+		key = func(hit *Hit) string {
+			return hit.VisitorID
+		}
+	case SegmentContextVisits:
+	default:
+		return s.predicate
+	}
+
+	selected := map[string]bool{}
+	for _, hit := range hits {
+		if s.predicate(hit) {
+			selected[key(hit)] = true
+		}
+	}
+	return func(hit *Hit) bool {
+		return selected[key(hit)]
+	}
+}
+
+// metricValue returns the value of a metric for the hits.
+func metricValue(metric string, hits []*Hit) float64 {
+	switch metric {
+	case MetricVisits:
+		visits := map[string]bool{}
+		for _, hit := range hits {
+			visits[hit.VisitorID+"\x00"+hit.VisitID] = true
+		}
+		return float64(len(visits))
+	case MetricVisitors:
+		visitors := map[string]bool{}
+		for _, hit := range hits {
+			visitors[hit.VisitorID] = true
+		}
+		return float64(len(visitors))
+	case MetricPageViews:
+		pageViews := 0
+		for _, hit := range hits {
+			if hit.PageView {
+				pageViews++
+			}
+		}
+		return float64(pageViews)
+	case MetricOccurrences:
+		return float64(len(hits))
+	}
+
+	sum := 0.0
+	for _, hit := range hits {
+		sum += hit.Events[metric]
+	}
+	return sum
+}
+
+// dimensionItem returns item ID and value of the dimension of a hit.
+// Hits without value are reported as "Unspecified" with item ID "0" if nones are returned.
+func dimensionItem(hit *Hit, dimension string, returnNones bool) (string, string, bool) {
+	if dimension == DimensionDay {
+		// e.g. "1200001" for 2020-01-01, made of the years since 1900 and the zero-based month
+		t := hit.Timestamp
+		itemID := fmt.Sprintf("%03d%02d%02d", t.Year()-1900, int(t.Month())-1, t.Day())
+		return itemID, t.Format("Jan 2, 2006"), true
+	}
+
+	value, ok := hit.Dimensions[dimension]
+	if !ok || value == "" {
+		return "0", "Unspecified", returnNones
+	}
+	return ItemID(value), value, true
+}
+
+// ItemID returns the item ID a ReportEngine reports for a dimension value.
+func ItemID(value string) string {
+	h := fnv.New32a()
+	h.Write([]byte(value))
+	return strconv.FormatUint(uint64(h.Sum32()), 10)
+}
+
+// sortRows sorts rows by the sorted metric column, by default descending by the first column, or by dimension value.
+func sortRows(rows []*engineRow, metrics []analytics.RankedRequestReportMetric, settings *analytics.RankedRequestSettings) {
+	column, direction := 0, "desc"
+	for i, metric := range metrics {
+		if metric.Sort != "" {
+			column, direction = i, strings.ToLower(metric.Sort)
+			break
+		}
+	}
+	byValue := settings != nil && settings.DimensionSort != ""
+	if byValue {
+		direction = strings.ToLower(settings.DimensionSort)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if !byValue && a.data[column] != b.data[column] {
+			if direction == "asc" {
+				return a.data[column] < b.data[column]
+			}
+			return a.data[column] > b.data[column]
+		}
+		if byValue && direction == "desc" {
+			return a.value > b.value
+		}
+		return a.value < b.value
+	})
+}
+
+// engineSearch represents the search of a ranked request
+type engineSearch struct {
+	itemIDs        map[string]bool
+	excludeItemIDs map[string]bool
+	contains       string
+}
+
+// containsClause matches a search clause like "CONTAINS 'checkout'"
+var containsClause = regexp.MustCompile(`(?i)^\s*CONTAINS\s+'([^']*)'\s*$`)
+
+// newEngineSearch returns the search of a ranked request, only CONTAINS clauses are supported.
+func newEngineSearch(search *analytics.RankedRequestSearch) (*engineSearch, error) {
+	s := &engineSearch{}
+	if search == nil {
+		return s, nil
+	}
+
+	if len(search.ItemIDs) > 0 {
+		s.itemIDs = map[string]bool{}
+		for _, itemID := range search.ItemIDs {
+			s.itemIDs[itemID] = true
+		}
+	}
+	s.excludeItemIDs = map[string]bool{}
+	for _, itemID := range search.ExcludeItemIDs {
+		s.excludeItemIDs[itemID] = true
+	}
+
+	if search.Clause != "" {
+		match := containsClause.FindStringSubmatch(search.Clause)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported search clause %q", search.Clause)
+		}
+		s.contains = strings.ToLower(match[1])
+	}
+	return s, nil
+}
+
+// matches returns true if the item matches the search.
+func (s *engineSearch) matches(itemID, value string) bool {
+	if s.itemIDs != nil && !s.itemIDs[itemID] {
+		return false
+	}
+	if s.excludeItemIDs[itemID] {
+		return false
+	}
+	return strings.Contains(strings.ToLower(value), s.contains)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analyticstest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/analyticstest"
+)
+
+func testHits() []analyticstest.Hit {
+	day1 := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2020, 4, 2, 10, 0, 0, 0, time.UTC)
+	hit := func(visitor, visit string, t time.Time, page string, events map[string]float64) analyticstest.Hit {
+		return analyticstest.Hit{
+			VisitorID:  visitor,
+			VisitID:    visit,
+			Timestamp:  t,
+			PageView:   page != "",
+			Dimensions: map[string]string{"variables/page": page},
+			Events:     events,
+		}
+	}
+	return []analyticstest.Hit{
+		hit("v1", "1", day1, "Home", nil),
+		hit("v1", "1", day1.Add(time.Minute), "Products", nil),
+		hit("v1", "1", day1.Add(2*time.Minute), "Checkout", map[string]float64{"metrics/orders": 1}),
+		hit("v1", "2", day2, "Home", nil),
+		hit("v2", "1", day1, "Home", nil),
+		hit("v2", "1", day1.Add(time.Minute), "", map[string]float64{"metrics/event1": 1}),
+		hit("v3", "1", day2, "Products", nil),
+		hit("v3", "1", day2.Add(time.Minute), "Products", nil),
+	}
+}
+
+func testRankedRequest(dimension string, metrics ...string) *analytics.RankedRequest {
+	reportMetrics := []analytics.RankedRequestReportMetric{}
+	for _, metric := range metrics {
+		reportMetrics = append(reportMetrics, analytics.RankedRequestReportMetric{ID: metric})
+	}
+	return &analytics.RankedRequest{
+		ReportSuiteID:   "amc.aem.prod",
+		Dimension:       dimension,
+		MetricContainer: &analytics.RankedRequestReportMetrics{Metrics: &reportMetrics},
+	}
+}
+
+func testRows(t *testing.T, report *analytics.RankedReportData, want map[string][]float32, order ...string) {
+	if len(*report.Rows) != len(order) {
+		t.Fatalf("Expected %d rows but got %d: %+v", len(order), len(*report.Rows), *report.Rows)
+	}
+	for i, row := range *report.Rows {
+		if row.Value != order[i] {
+			t.Errorf("Expected row %d %q but got %q", i, order[i], row.Value)
+		}
+		if row.ItemID != analyticstest.ItemID(row.Value) {
+			t.Errorf("Expected item ID %q but got %q", analyticstest.ItemID(row.Value), row.ItemID)
+		}
+		for j, value := range want[row.Value] {
+			if row.Data[j] != value {
+				t.Errorf("Row %q column %d: %v, want %v", row.Value, j, row.Data[j], value)
+			}
+		}
+	}
+}
+
+func TestReportEngineRun(t *testing.T) {
+	engine := analyticstest.NewReportEngine(testHits()...)
+
+	report, err := engine.Run(testRankedRequest("variables/page",
+		analyticstest.MetricPageViews, analyticstest.MetricVisits, analyticstest.MetricVisitors, "metrics/orders"))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	testRows(t, report, map[string][]float32{
+		"Home":     {3, 3, 2, 0},
+		"Products": {3, 2, 2, 0},
+		"Checkout": {1, 1, 1, 1},
+	}, "Home", "Products", "Checkout")
+
+	totals := report.SummaryData.Totals
+	if totals[0] != 7 || totals[1] != 4 || totals[2] != 3 || totals[3] != 1 {
+		t.Errorf("Unexpected totals %v", totals)
+	}
+	if report.TotalElements != 3 || report.Columns.ColumnIDs[3] != "3" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestReportEngineFilters(t *testing.T) {
+	engine := analyticstest.NewReportEngine(testHits()...)
+	engine.AddSegment("s_checkout", analyticstest.SegmentContextVisits, func(hit *analyticstest.Hit) bool {
+		return hit.Dimensions["variables/page"] == "Checkout"
+	})
+
+	// date range and visit segment
+	req := testRankedRequest("variables/page", analyticstest.MetricOccurrences)
+	req.GlobalFilters = &[]analytics.RankedRequestReportFilter{
+		{Type: "dateRange", DateRange: "2020-04-01T00:00:00.000/2020-04-02T00:00:00.000"},
+		{Type: "segment", SegmentID: "s_checkout"},
+	}
+	report, err := engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, map[string][]float32{
+		"Home":     {1},
+		"Products": {1},
+		"Checkout": {1},
+	}, "Checkout", "Home", "Products")
+
+	// metric filter breaking down by visitor
+	req = testRankedRequest("variables/page", analyticstest.MetricVisits, analyticstest.MetricVisits)
+	req.MetricContainer.MetricFilters = &[]analytics.RankedRequestReportFilter{
+		{ID: "0", Type: "breakdown", Dimension: "variables/page", ItemID: analyticstest.ItemID("Home")},
+	}
+	(*req.MetricContainer.Metrics)[1].Filters = []string{"0"}
+	report, err = engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, map[string][]float32{
+		"Home":     {3, 3},
+		"Products": {2, 0},
+		"Checkout": {1, 0},
+	}, "Home", "Products", "Checkout")
+
+	// search
+	req = testRankedRequest("variables/page", analyticstest.MetricVisits)
+	req.Search = &analytics.RankedRequestSearch{Clause: "CONTAINS 'o'", ExcludeItemIDs: []string{analyticstest.ItemID("Home")}}
+	report, err = engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, nil, "Products", "Checkout")
+}
+
+func TestReportEngineDateRangeLastDay(t *testing.T) {
+	last := time.Date(2020, 4, 2, 23, 59, 59, 999500000, time.UTC)
+	engine := analyticstest.NewReportEngine(append(testHits(),
+		analyticstest.Hit{VisitorID: "v4", VisitID: "1", Timestamp: last, PageView: true, Dimensions: map[string]string{"variables/page": "Checkout"}},
+		analyticstest.Hit{VisitorID: "v4", VisitID: "1", Timestamp: last.Add(time.Millisecond), PageView: true, Dimensions: map[string]string{"variables/page": "Checkout"}},
+	)...)
+
+	req := testRankedRequest("variables/page", analyticstest.MetricPageViews)
+	req.GlobalFilters = &[]analytics.RankedRequestReportFilter{
+		{Type: "dateRange", DateRange: "2020-04-02T00:00:00.000/2020-04-02T23:59:59.999"},
+	}
+	report, err := engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, map[string][]float32{"Products": {2}, "Home": {1}, "Checkout": {1}}, "Products", "Checkout", "Home")
+}
+
+func TestReportEngineSortAndPages(t *testing.T) {
+	engine := analyticstest.NewReportEngine(testHits()...)
+
+	req := testRankedRequest("variables/page", analyticstest.MetricVisits)
+	req.Settings = &analytics.RankedRequestSettings{Limit: 2, Page: 1, DimensionSort: "asc", NonesBehavior: "return-nones"}
+	report, err := engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if report.TotalPages != 2 || !report.LastPage || report.TotalElements != 4 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if len(*report.Rows) != 2 || (*report.Rows)[0].Value != "Products" || (*report.Rows)[1].ItemID != "0" {
+		t.Errorf("Unexpected rows %+v", *report.Rows)
+	}
+
+	req = testRankedRequest(analyticstest.DimensionDay, analyticstest.MetricVisits)
+	(*req.MetricContainer.Metrics)[0].Sort = "asc"
+	report, err = engine.Run(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	rows := *report.Rows
+	if len(rows) != 2 || rows[0].ItemID != "1200301" || rows[0].Data[0] != 2 || rows[1].Value != "Apr 2, 2020" {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+}
+
+func TestReportEngineErrors(t *testing.T) {
+	engine := analyticstest.NewReportEngine(testHits()...)
+
+	if _, err := engine.Run(testRankedRequest("variables/page")); err == nil {
+		t.Errorf("Expected missing metrics error but got none")
+	}
+
+	req := testRankedRequest("variables/page", analyticstest.MetricVisits)
+	req.GlobalFilters = &[]analytics.RankedRequestReportFilter{{Type: "segment", SegmentID: "unknown"}}
+	if _, err := engine.Run(req); err == nil {
+		t.Errorf("Expected unknown segment error but got none")
+	}
+
+	req = testRankedRequest("variables/page", analyticstest.MetricVisits)
+	req.Search = &analytics.RankedRequestSearch{Clause: "MATCH 'Home'"}
+	if _, err := engine.Run(req); err == nil {
+		t.Errorf("Expected unsupported search error but got none")
+	}
+}
+
+func TestServerReportEngine(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	server.SetReportEngine("amc.aem.prod", analyticstest.NewReportEngine(testHits()...))
+	client := server.Client()
+
+	report, err := client.Reports.Run(testRankedRequest("variables/page", analyticstest.MetricPageViews))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, map[string][]float32{"Home": {3}, "Products": {3}, "Checkout": {1}}, "Home", "Products", "Checkout")
+
+	_, err = client.Reports.Run(testRankedRequest("variables/page"))
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestReportEngineConcurrent(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	engine := analyticstest.NewReportEngine()
+	server.SetReportEngine("amc.aem.prod", engine)
+	client := server.Client()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, hit := range testHits() {
+			engine.AddHits(hit)
+			engine.AddSegment("s_"+hit.VisitorID, analyticstest.SegmentContextVisitors, func(h *analyticstest.Hit) bool {
+				return h.VisitorID == hit.VisitorID
+			})
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Reports.Run(testRankedRequest("variables/page", analyticstest.MetricPageViews)); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+	<-done
+
+	report, err := client.Reports.Run(testRankedRequest("variables/page", analyticstest.MetricPageViews))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	testRows(t, report, map[string][]float32{"Home": {3}, "Products": {3}, "Checkout": {1}}, "Home", "Products", "Checkout")
+}
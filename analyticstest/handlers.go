@@ -0,0 +1,383 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analyticstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+// page represents a page of components
+type page struct {
+	Content          interface{} `json:"content"`
+	Number           int         `json:"number"`
+	Size             int         `json:"size"`
+	NumberOfElements int         `json:"numberOfElements"`
+	TotalElements    int         `json:"totalElements"`
+	PreviousPage     bool        `json:"previousPage"`
+	FirstPage        bool        `json:"firstPage"`
+	NextPage         bool        `json:"nextPage"`
+	LastPage         bool        `json:"lastPage"`
+	TotalPages       int         `json:"totalPages"`
+}
+
+// errorResponse represents an API error
+type errorResponse struct {
+	ErrorCode        string `json:"errorCode"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+// route serves the endpoint of a path relative to the company.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	params := r.URL.Query()
+	segments := strings.SplitN(strings.Trim(path, "/"), "/", 2)
+	id := ""
+	if len(segments) > 1 {
+		id = segments[1]
+	}
+
+	switch {
+	case segments[0] == "segments" && r.Method == http.MethodGet:
+		s.getSegments(w, id, params)
+	case segments[0] == "calculatedmetrics" && r.Method == http.MethodGet:
+		s.getCalculatedMetrics(w, id, params)
+	case segments[0] == "dateranges":
+		s.dateRangesEndpoint(w, r.Method, id, params, body)
+	case segments[0] == "dimensions" && r.Method == http.MethodGet:
+		s.getDimensions(w, id, params)
+	case segments[0] == "metrics" && r.Method == http.MethodGet:
+		s.getMetrics(w, id, params)
+	case path == "/collections/suites" && r.Method == http.MethodGet:
+		s.getCollections(w, "", params)
+	case strings.HasPrefix(path, "/collections/suites/") && r.Method == http.MethodGet:
+		s.getCollections(w, strings.TrimPrefix(path, "/collections/suites/"), params)
+	case path == "/users/me" && r.Method == http.MethodGet:
+		if s.currentUser == nil {
+			writeError(w, http.StatusNotFound, "no current user")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.currentUser)
+	case path == "/users" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, newPage(s.users, params))
+	case path == "/reports" && r.Method == http.MethodPost:
+		s.runReport(w, body)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown endpoint %s %s", r.Method, path))
+	}
+}
+
+// getSegments serves a segment or a page of segments filtered by rsids and name.
+func (s *Server) getSegments(w http.ResponseWriter, id string, params url.Values) {
+	if id != "" {
+		for _, segment := range s.segments {
+			if segment.ID == id {
+				writeJSON(w, http.StatusOK, segment)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("segment %s not found", id))
+		return
+	}
+
+	segments := []analytics.Segment{}
+	for _, segment := range s.segments {
+		if matchesList(segment.ReportSuiteID, params.Get("rsids")) && matchesName(segment.Name, params.Get("name")) {
+			segments = append(segments, segment)
+		}
+	}
+	writeJSON(w, http.StatusOK, newPage(segments, params))
+}
+
+// getCalculatedMetrics serves a calculated metric or a page of calculated metrics filtered by rsids, IDs and name.
+func (s *Server) getCalculatedMetrics(w http.ResponseWriter, id string, params url.Values) {
+	if id != "" {
+		for _, calculatedMetric := range s.calculatedMetrics {
+			if calculatedMetric.ID == id {
+				writeJSON(w, http.StatusOK, calculatedMetric)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("calculated metric %s not found", id))
+		return
+	}
+
+	calculatedMetrics := []analytics.CalculatedMetric{}
+	for _, calculatedMetric := range s.calculatedMetrics {
+		if matchesList(calculatedMetric.RSID, params.Get("rsids")) &&
+			matchesList(calculatedMetric.ID, params.Get("filterByIds")) &&
+			matchesName(calculatedMetric.Name, params.Get("name")) {
+			calculatedMetrics = append(calculatedMetrics, calculatedMetric)
+		}
+	}
+	writeJSON(w, http.StatusOK, newPage(calculatedMetrics, params))
+}
+
+// dateRangesEndpoint serves, creates, updates and deletes date ranges.
+func (s *Server) dateRangesEndpoint(w http.ResponseWriter, method, id string, params url.Values, body []byte) {
+	index := -1
+	for i, dateRange := range s.dateRanges {
+		if id != "" && dateRange.ID == id {
+			index = i
+		}
+	}
+	if id != "" && index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("date range %s not found", id))
+		return
+	}
+
+	switch {
+	case method == http.MethodGet && id == "":
+		dateRanges := []analytics.DateRange{}
+		for _, dateRange := range s.dateRanges {
+			if matchesList(dateRange.ID, params.Get("filterByIds")) {
+				dateRanges = append(dateRanges, dateRange)
+			}
+		}
+		writeJSON(w, http.StatusOK, newPage(dateRanges, params))
+	case method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.dateRanges[index])
+	case method == http.MethodPost && id == "":
+		var dateRange analytics.DateRange
+		if err := json.Unmarshal(body, &dateRange); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.nextID++
+		dateRange.ID = fmt.Sprintf("%024x", s.nextID)
+		s.dateRanges = append(s.dateRanges, dateRange)
+		writeJSON(w, http.StatusOK, dateRange)
+	case method == http.MethodPut && id != "":
+		var dateRange analytics.DateRange
+		if err := json.Unmarshal(body, &dateRange); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dateRange.ID = id
+		s.dateRanges[index] = dateRange
+		writeJSON(w, http.StatusOK, dateRange)
+	case method == http.MethodDelete && id != "":
+		s.dateRanges = append(s.dateRanges[:index], s.dateRanges[index+1:]...)
+		writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// getDimensions serves a dimension or the dimensions of a report suite.
+func (s *Server) getDimensions(w http.ResponseWriter, id string, params url.Values) {
+	rsid := params.Get("rsid")
+	if rsid == "" {
+		writeError(w, http.StatusBadRequest, "missing rsid")
+		return
+	}
+
+	dimensions := s.dimensions[rsid]
+	if id == "" {
+		writeJSON(w, http.StatusOK, append([]analytics.Dimension{}, dimensions...))
+		return
+	}
+	for _, dimension := range dimensions {
+		if dimension.ID == id || dimension.ID == "variables/"+id {
+			writeJSON(w, http.StatusOK, dimension)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("dimension %s not found", id))
+}
+
+// getMetrics serves a metric or the metrics of a report suite.
+func (s *Server) getMetrics(w http.ResponseWriter, id string, params url.Values) {
+	rsid := params.Get("rsid")
+	if rsid == "" {
+		writeError(w, http.StatusBadRequest, "missing rsid")
+		return
+	}
+
+	metrics := s.metrics[rsid]
+	if id == "" {
+		writeJSON(w, http.StatusOK, append([]analytics.Metric{}, metrics...))
+		return
+	}
+	for _, metric := range metrics {
+		if metric.ID == id || metric.ID == "metrics/"+id {
+			writeJSON(w, http.StatusOK, metric)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("metric %s not found", id))
+}
+
+// getCollections serves a report suite or a page of report suites filtered by rsids and rsidContains.
+func (s *Server) getCollections(w http.ResponseWriter, id string, params url.Values) {
+	if id != "" {
+		for _, collection := range s.collections {
+			if collection.RSID == id {
+				writeJSON(w, http.StatusOK, collection)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("report suite %s not found", id))
+		return
+	}
+
+	collections := []analytics.Collection{}
+	for _, collection := range s.collections {
+		if matchesList(collection.RSID, params.Get("rsids")) &&
+			strings.Contains(collection.RSID, params.Get("rsidContains")) {
+			collections = append(collections, collection)
+		}
+	}
+	writeJSON(w, http.StatusOK, newPage(collections, params))
+}
+
+// runReport serves a page of the report set for the report suite and dimension of the request or runs it with the report engine.
+func (s *Server) runReport(w http.ResponseWriter, body []byte) {
+	var request analytics.RankedRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.ReportSuiteID == "" || request.Dimension == "" {
+		writeError(w, http.StatusBadRequest, "missing rsid or dimension")
+		return
+	}
+
+	_, seeded := s.reports[reportKey(request.ReportSuiteID, request.Dimension)]
+	if engine, ok := s.engines[request.ReportSuiteID]; ok && !seeded {
+		report, err := engine.Run(&request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	limit, number := 50, 0
+	if request.Settings != nil {
+		if request.Settings.Limit > 0 {
+			limit = request.Settings.Limit
+		}
+		number = request.Settings.Page
+	}
+
+	rows := []analytics.RankedReportRowData{}
+	report := analytics.RankedReportData{}
+	if seeded, ok := s.reports[reportKey(request.ReportSuiteID, request.Dimension)]; ok {
+		report = *seeded
+		if seeded.Rows != nil {
+			rows = *seeded.Rows
+		}
+	}
+
+	start, end := pageBounds(len(rows), limit, number)
+	pageRows := append([]analytics.RankedReportRowData{}, rows[start:end]...)
+	report.Rows = &pageRows
+	report.Number = number
+	report.NumberOfElements = len(pageRows)
+	report.TotalElements = len(rows)
+	report.TotalPages = totalPages(len(rows), limit)
+	report.FirstPage = number == 0
+	report.LastPage = number >= report.TotalPages-1
+	writeJSON(w, http.StatusOK, report)
+}
+
+// newPage returns a page of the items slice according to the limit and page params.
+func newPage(items interface{}, params url.Values) *page {
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	number, _ := strconv.Atoi(params.Get("page"))
+
+	v := reflect.ValueOf(items)
+	start, end := pageBounds(v.Len(), limit, number)
+	pages := totalPages(v.Len(), limit)
+	return &page{
+		Content:          v.Slice(start, end).Interface(),
+		Number:           number,
+		Size:             limit,
+		NumberOfElements: end - start,
+		TotalElements:    v.Len(),
+		PreviousPage:     number > 0,
+		FirstPage:        number == 0,
+		NextPage:         number < pages-1,
+		LastPage:         number >= pages-1,
+		TotalPages:       pages,
+	}
+}
+
+// pageBounds returns the bounds of a page of n items.
+func pageBounds(n, limit, number int) (int, int) {
+	start := limit * number
+	if number < 0 || start > n {
+		start = n
+	}
+	end := start + limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// totalPages returns the number of pages of n items.
+func totalPages(n, limit int) int {
+	return (n + limit - 1) / limit
+}
+
+// matchesList returns true if list is empty or contains the comma separated value.
+func matchesList(value, list string) bool {
+	if list == "" {
+		return true
+	}
+	for _, item := range strings.Split(list, ",") {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesName returns true if name is empty or contained in value, ignoring case.
+func matchesName(value, name string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(name))
+}
+
+// reportKey returns the key of a report.
+func reportKey(rsid, dimension string) string {
+	return rsid + "/" + dimension
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, statusCode int, description string) {
+	writeJSON(w, statusCode, errorResponse{
+		ErrorCode:        strings.ToLower(strings.Replace(http.StatusText(statusCode), " ", "_", -1)),
+		ErrorDescription: description,
+	})
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package analyticstest provides an in-memory fake of the Analytics API 2.0 for tests.
+package analyticstest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+// CompanyID is the global company ID served by a Server
+const CompanyID = "testCompany"
+
+// Request represents a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Params url.Values
+	Body   []byte
+}
+
+// Fault represents a fault injected into the responses of a Server.
+// Method and Path select the affected requests, Path is a prefix relative to the company, e.g. "/reports".
+// An empty Method or Path matches all requests. A fault applies to the next Times requests, or to all requests if Times is 0.
+// The response is delayed by Latency, a StatusCode other than 0 replaces the response, e.g. 429 or 503.
+type Fault struct {
+	Method     string
+	Path       string
+	Times      int
+	Latency    time.Duration
+	StatusCode int
+	Header     http.Header
+}
+
+// Server is a fake Analytics API server with seedable data.
+// It implements segments, calculated metrics, date ranges, dimensions, metrics, collections, users and reports endpoints.
+type Server struct {
+	// URL is the base URL of the server, e.g. for Config.BaseURL
+	URL string
+
+	server *httptest.Server
+
+	mu                sync.Mutex
+	segments          []analytics.Segment
+	calculatedMetrics []analytics.CalculatedMetric
+	dateRanges        []analytics.DateRange
+	dimensions        map[string][]analytics.Dimension
+	metrics           map[string][]analytics.Metric
+	collections       []analytics.Collection
+	users             []analytics.User
+	currentUser       *analytics.User
+	reports           map[string]*analytics.RankedReportData
+	engines           map[string]*ReportEngine
+	faults            []*Fault
+	requests          []Request
+	nextID            int
+}
+
+// NewServer starts and returns a new Server, it should be closed when finished.
+func NewServer() *Server {
+	s := &Server{
+		dimensions: map[string][]analytics.Dimension{},
+		metrics:    map[string][]analytics.Metric{},
+		reports:    map[string]*analytics.RankedReportData{},
+		engines:    map[string]*ReportEngine{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL + "/api"
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a client configuration for the server.
+func (s *Server) Config() *analytics.Config {
+	return &analytics.Config{
+		HTTPClient:  s.server.Client(),
+		BaseURL:     s.URL,
+		ClientID:    "testClientId",
+		OrgID:       "testOrgId@AdobeOrg",
+		AccessToken: "testAccessToken",
+		CompanyID:   CompanyID,
+	}
+}
+
+// Client returns a client for the server.
+func (s *Server) Client() *analytics.Client {
+	client, err := analytics.NewClient(s.Config())
+	if err != nil {
+		// the configuration is always valid
+		panic(err)
+	}
+	return client
+}
+
+// AddSegments adds segments.
+func (s *Server) AddSegments(segments ...analytics.Segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segments = append(s.segments, segments...)
+}
+
+// AddCalculatedMetrics adds calculated metrics.
+func (s *Server) AddCalculatedMetrics(calculatedMetrics ...analytics.CalculatedMetric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calculatedMetrics = append(s.calculatedMetrics, calculatedMetrics...)
+}
+
+// AddDateRanges adds date ranges.
+func (s *Server) AddDateRanges(dateRanges ...analytics.DateRange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dateRanges = append(s.dateRanges, dateRanges...)
+}
+
+// AddDimensions adds dimensions of a report suite.
+func (s *Server) AddDimensions(rsid string, dimensions ...analytics.Dimension) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dimensions[rsid] = append(s.dimensions[rsid], dimensions...)
+}
+
+// AddMetrics adds metrics of a report suite.
+func (s *Server) AddMetrics(rsid string, metrics ...analytics.Metric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics[rsid] = append(s.metrics[rsid], metrics...)
+}
+
+// AddCollections adds report suites.
+func (s *Server) AddCollections(collections ...analytics.Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = append(s.collections, collections...)
+}
+
+// AddUsers adds users, the first user becomes the current user unless one is set.
+func (s *Server) AddUsers(users ...analytics.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = append(s.users, users...)
+	if s.currentUser == nil && len(s.users) > 0 {
+		// copy the user, s.users may be reallocated by later appends
+		user := s.users[0]
+		s.currentUser = &user
+	}
+}
+
+// SetCurrentUser sets the user returned by /users/me.
+func (s *Server) SetCurrentUser(user analytics.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentUser = &user
+}
+
+// SetReport sets the report of a report suite and dimension.
+// The rows are paginated according to the request settings.
+// Reports which are neither set nor answered by a ReportEngine have no rows.
+func (s *Server) SetReport(rsid, dimension string, report *analytics.RankedReportData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports[reportKey(rsid, dimension)] = report
+}
+
+// SetReportEngine sets the engine answering the reports of a report suite.
+// Reports set with SetReport take precedence.
+func (s *Server) SetReportEngine(rsid string, engine *ReportEngine) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.engines[rsid] = engine
+}
+
+// AddFault injects a fault.
+func (s *Server) AddFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the received requests.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// serveHTTP records the request, applies faults and serves the endpoint.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/api/" + CompanyID
+	if !strings.HasPrefix(r.URL.Path, prefix+"/") {
+		writeError(w, http.StatusNotFound, "unknown company")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	body, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Params: r.URL.Query(), Body: body})
+	fault := s.fault(r.Method, path)
+	s.mu.Unlock()
+
+	if r.Header.Get("Authorization") == "" || r.Header.Get("x-api-key") == "" {
+		writeError(w, http.StatusUnauthorized, "missing credentials")
+		return
+	}
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			for key, values := range fault.Header {
+				w.Header()[key] = values
+			}
+			writeError(w, fault.StatusCode, http.StatusText(fault.StatusCode))
+			return
+		}
+	}
+
+	s.route(w, r, path, body)
+}
+
+// fault returns the first fault matching the request and counts its use, the caller must hold the lock.
+func (s *Server) fault(method, path string) *Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != method {
+			continue
+		}
+		if fault.Path != "" && !strings.HasPrefix(path, fault.Path) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package analyticstest_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/analyticstest"
+)
+
+func TestServerSegments(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	server.AddSegments(
+		analytics.Segment{ID: "s300000000_1", Name: "Visits from Germany", ReportSuiteID: "amc.aem.prod"},
+		analytics.Segment{ID: "s300000000_2", Name: "Visits from France", ReportSuiteID: "amc.aem.prod"},
+		analytics.Segment{ID: "s300000000_3", Name: "Visits from Germany", ReportSuiteID: "amc.aem.dev"},
+	)
+	client := server.Client()
+
+	segments, err := client.Segments.GetAll("amc.aem.prod", "", "", "", "", "", 1, 1, "", "", nil, nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if segments.TotalElements != 2 || segments.TotalPages != 2 || !segments.LastPage {
+		t.Errorf("Unexpected page %+v", segments)
+	}
+	if len(*segments.Content) != 1 || (*segments.Content)[0].ID != "s300000000_2" {
+		t.Errorf("Unexpected segments %+v", *segments.Content)
+	}
+
+	segments, err = client.Segments.GetAll("", "", "", "germany", "", "", 10, 0, "", "", nil, nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if segments.TotalElements != 2 {
+		t.Errorf("Expected %d segments but got %d", 2, segments.TotalElements)
+	}
+
+	segment, err := client.Segments.GetByID("s300000000_3", "", nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if segment.ReportSuiteID != "amc.aem.dev" {
+		t.Errorf("Expected rsid %q but got %q", "amc.aem.dev", segment.ReportSuiteID)
+	}
+
+	_, err = client.Segments.GetByID("s300000000_4", "", nil)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
+
+func TestServerDateRanges(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	created, err := client.DateRanges.Create(&analytics.DateRange{Name: "Last week"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if created.ID == "" {
+		t.Errorf("Expected an ID")
+	}
+
+	_, err = client.DateRanges.Update(created.ID, &analytics.DateRange{Name: "Last 7 days"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	dateRange, err := client.DateRanges.GetByID(created.ID, "", nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if dateRange.Name != "Last 7 days" {
+		t.Errorf("Expected name %q but got %q", "Last 7 days", dateRange.Name)
+	}
+
+	err = client.DateRanges.Delete(created.ID)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	dateRanges, err := client.DateRanges.GetAll("", "", 10, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if dateRanges.TotalElements != 0 {
+		t.Errorf("Expected %d date ranges but got %d", 0, dateRanges.TotalElements)
+	}
+}
+
+func TestServerMetadata(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	server.AddDimensions("amc.aem.prod", analytics.Dimension{ID: "variables/page"}, analytics.Dimension{ID: "variables/evar1"})
+	server.AddMetrics("amc.aem.prod", analytics.Metric{ID: "metrics/pageviews"})
+	server.AddCollections(analytics.Collection{RSID: "amc.aem.prod"}, analytics.Collection{RSID: "amc.aem.dev"})
+	server.AddCalculatedMetrics(analytics.CalculatedMetric{ID: "cm300000000_1", RSID: "amc.aem.prod"})
+	server.AddUsers(analytics.User{Login: "jdoe"}, analytics.User{Login: "jroe"})
+	client := server.Client()
+
+	dimensions, err := client.Dimensions.GetAll("amc.aem.prod", "", false, false, false, nil)
+	if err != nil || len(*dimensions) != 2 {
+		t.Errorf("Unexpected dimensions %v (%v)", dimensions, err)
+	}
+	dimension, err := client.Dimensions.GetByID("amc.aem.prod", "evar1", "", nil)
+	if err != nil || dimension.ID != "variables/evar1" {
+		t.Errorf("Unexpected dimension %v (%v)", dimension, err)
+	}
+	metric, err := client.Metrics.GetByID("amc.aem.prod", "pageviews", "", nil)
+	if err != nil || metric.ID != "metrics/pageviews" {
+		t.Errorf("Unexpected metric %v (%v)", metric, err)
+	}
+	collections, err := client.Collections.GetAll("", "dev", 10, 0, nil)
+	if err != nil || collections.TotalElements != 1 {
+		t.Errorf("Unexpected collections %v (%v)", collections, err)
+	}
+	calculatedMetric, err := client.CalculatedMetrics.GetByID("cm300000000_1", "", nil)
+	if err != nil || calculatedMetric.RSID != "amc.aem.prod" {
+		t.Errorf("Unexpected calculated metric %v (%v)", calculatedMetric, err)
+	}
+	user, err := client.Users.GetCurrent()
+	if err != nil || user.Login != "jdoe" {
+		t.Errorf("Unexpected current user %v (%v)", user, err)
+	}
+	users, err := client.Users.GetAll(10, 0)
+	if err != nil || users.TotalElements != 2 {
+		t.Errorf("Unexpected users %v (%v)", users, err)
+	}
+}
+
+func TestServerCurrentUser(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	if _, err := client.Users.GetCurrent(); err == nil {
+		t.Errorf("Expected error but got none")
+	}
+
+	server.AddUsers(analytics.User{Login: "jdoe"})
+	for i := 0; i < 10; i++ {
+		server.AddUsers(analytics.User{Login: fmt.Sprintf("user%d", i)})
+	}
+	user, err := client.Users.GetCurrent()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if user.Login != "jdoe" {
+		t.Errorf("Expected jdoe but got %s", user.Login)
+	}
+
+	server.SetCurrentUser(analytics.User{Login: "jroe"})
+	server.AddUsers(analytics.User{Login: "jsmith"})
+	user, err = client.Users.GetCurrent()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if user.Login != "jroe" {
+		t.Errorf("Expected jroe but got %s", user.Login)
+	}
+}
+
+func TestServerReports(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	rows := []analytics.RankedReportRowData{}
+	for i := 0; i < 5; i++ {
+		rows = append(rows, analytics.RankedReportRowData{Value: string(rune('a' + i)), Data: []float32{float32(i)}})
+	}
+	server.SetReport("amc.aem.prod", "variables/page", &analytics.RankedReportData{Rows: &rows})
+	client := server.Client()
+
+	report, err := client.Reports.Run(&analytics.RankedRequest{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+		Settings:      &analytics.RankedRequestSettings{Limit: 2, Page: 2},
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if report.TotalPages != 3 || !report.LastPage || len(*report.Rows) != 1 || (*report.Rows)[0].Value != "e" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	report, err = client.Reports.Run(&analytics.RankedRequest{ReportSuiteID: "amc.aem.prod", Dimension: "variables/evar1"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(*report.Rows) != 0 {
+		t.Errorf("Expected %d rows but got %d", 0, len(*report.Rows))
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	server := analyticstest.NewServer()
+	defer server.Close()
+
+	server.AddUsers(analytics.User{Login: "jdoe"})
+	server.AddFault(analyticstest.Fault{
+		Path:       "/users",
+		Times:      2,
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"1"}},
+	})
+	client := server.Client()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Users.GetCurrent(); err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	}
+	if _, err := client.Users.GetCurrent(); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	server.AddFault(analyticstest.Fault{Method: http.MethodGet, Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := client.Users.GetCurrent(); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected latency of at least %s but got %s", 50*time.Millisecond, elapsed)
+	}
+
+	server.ClearFaults()
+	server.AddFault(analyticstest.Fault{Path: "/reports", StatusCode: http.StatusServiceUnavailable})
+	if _, err := client.Users.GetCurrent(); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 5 || requests[0].Path != "/users/me" {
+		t.Errorf("Unexpected requests %+v", requests)
+	}
+}
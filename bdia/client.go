@@ -0,0 +1,204 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package bdia builds and uploads files of the Adobe Analytics Bulk Data Insertion API (BDIA).
+// BDIA files are gzipped CSV files of hits, the hits of a visitor belong to one visitor group.
+// BDIA docs: https://experienceleague.adobe.com/docs/analytics/import/c-bulk-data-insertion.html
+package bdia
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adobe/aa-client-go/analytics"
+)
+
+const (
+	// DefaultBaseURL is the URL of the BDIA endpoints
+	DefaultBaseURL = "https://analytics-collection.adobe.io"
+
+	uploadPath   = "/aa/collect/v1/events"
+	validatePath = "/aa/collect/v1/events/validate"
+)
+
+// Config holds configuration values
+type Config struct {
+	HTTPClient *http.Client
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL     string
+	ClientID    string
+	AccessToken string
+	// TokenSource takes precedence over the AccessToken.
+	TokenSource analytics.TokenSource
+
+	// ValidateOnly validates uploaded files without ingesting their hits.
+	ValidateOnly bool
+}
+
+// UploadResult represents the response of an upload
+type UploadResult struct {
+	FileID         string `json:"file_id,omitempty"`
+	VisitorGroupID string `json:"visitor_group_id,omitempty"`
+	Size           int64  `json:"size,omitempty"`
+	ReceivedDate   int64  `json:"received_date,omitempty"`
+	Rows           int    `json:"rows,omitempty"`
+	InvalidRows    int    `json:"invalid_rows,omitempty"`
+	UploadName     string `json:"upload_name,omitempty"`
+	Success        bool   `json:"success"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// APIError is returned if BDIA rejected a file.
+type APIError struct {
+	StatusCode int
+	ErrorCode  string `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("received unexpected status code %d: %s %s", e.StatusCode, e.ErrorCode, e.Message)
+}
+
+// Client uploads files to BDIA.
+type Client struct {
+	httpClient   *http.Client
+	baseURL      *url.URL
+	clientID     string
+	tokenSource  analytics.TokenSource
+	validateOnly bool
+}
+
+// NewClient returns a new BDIA client.
+func NewClient(config *Config) (*Client, error) {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	parsedBaseURL, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("malformed URL")
+	}
+	if parsedBaseURL.Scheme == "" {
+		return nil, fmt.Errorf("missing URL scheme")
+	}
+	if parsedBaseURL.Host == "" {
+		return nil, fmt.Errorf("missing URL host")
+	}
+
+	tokenSource := config.TokenSource
+	if tokenSource == nil && config.AccessToken != "" {
+		tokenSource = analytics.StaticTokenSource(config.AccessToken)
+	}
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("missing ClientID")
+	}
+	if tokenSource == nil {
+		return nil, fmt.Errorf("missing AccessToken")
+	}
+
+	return &Client{
+		httpClient:   httpClient,
+		baseURL:      parsedBaseURL,
+		clientID:     config.ClientID,
+		tokenSource:  tokenSource,
+		validateOnly: config.ValidateOnly,
+	}, nil
+}
+
+// Upload uploads a file with the visitor group ID of the file.
+// In validate-only mode the file is only validated, see Config.ValidateOnly.
+func (client *Client) Upload(file *File) (*UploadResult, error) {
+	if client.validateOnly {
+		return client.Validate(file)
+	}
+	return client.upload(uploadPath, file)
+}
+
+// Validate validates a file without ingesting its hits.
+func (client *Client) Validate(file *File) (*UploadResult, error) {
+	return client.upload(validatePath, file)
+}
+
+// UploadFiles uploads the files in their order and stops at the first error.
+// The results of the uploaded files are returned with the error.
+func (client *Client) UploadFiles(files []*File) ([]*UploadResult, error) {
+	var results []*UploadResult
+	for _, file := range files {
+		result, err := client.Upload(file)
+		if err != nil {
+			return results, fmt.Errorf("%s: %v", file.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// upload posts the file as multipart form to the path
+func (client *Client) upload(path string, file *File) (*UploadResult, error) {
+	accessToken, err := client.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", file.Name)
+	if err != nil {
+		return nil, err
+	}
+	part.Write(file.Data)
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, client.baseURL.String()+path, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	req.Header.Set("x-api-key", client.clientID)
+	req.Header.Set("x-adobe-vgid", file.VisitorGroupID)
+
+	res, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c := res.StatusCode; c < 200 || c > 299 {
+		apiErr := &APIError{StatusCode: res.StatusCode}
+		json.Unmarshal(data, apiErr)
+		return nil, apiErr
+	}
+
+	var result UploadResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
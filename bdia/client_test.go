@@ -0,0 +1,148 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package bdia_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/aa-client-go/bdia"
+	"github.com/adobe/aa-client-go/datainsertion"
+)
+
+// uploadServer is a BDIA stand-in which records the uploads
+type uploadServer struct {
+	*httptest.Server
+	paths  []string
+	vgids  []string
+	files  [][]byte
+	status int
+}
+
+func newUploadServer() *uploadServer {
+	s := &uploadServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer testAccessToken" || r.Header.Get("x-api-key") != "testClientId" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		s.paths = append(s.paths, r.URL.Path)
+		s.vgids = append(s.vgids, r.Header.Get("x-adobe-vgid"))
+		s.files = append(s.files, data)
+
+		w.WriteHeader(s.status)
+		if s.status != http.StatusOK {
+			w.Write([]byte(`{"error_code":"invalid_file","message":"invalid row"}`))
+			return
+		}
+		w.Write([]byte(`{"file_id":"file_1","visitor_group_id":"` + r.Header.Get("x-adobe-vgid") + `","rows":1,"upload_name":"` + header.Filename + `","success":true}`))
+	}))
+	return s
+}
+
+func newClient(t *testing.T, server *uploadServer, validateOnly bool) *bdia.Client {
+	client, err := bdia.NewClient(&bdia.Config{
+		HTTPClient:   server.Client(),
+		BaseURL:      server.URL,
+		ClientID:     "testClientId",
+		AccessToken:  "testAccessToken",
+		ValidateOnly: validateOnly,
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		config *bdia.Config
+		err    string
+	}{
+		{&bdia.Config{ClientID: "id", AccessToken: "token"}, ""},
+		{&bdia.Config{AccessToken: "token"}, "missing ClientID"},
+		{&bdia.Config{ClientID: "id"}, "missing AccessToken"},
+		{&bdia.Config{BaseURL: "analytics-collection.adobe.io", ClientID: "id", AccessToken: "token"}, "missing URL scheme"},
+	}
+
+	for i, test := range tests {
+		_, err := bdia.NewClient(test.config)
+		if test.err == "" && err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("Test %d: Expected error %q but got %v", i, test.err, err)
+		}
+	}
+}
+
+func TestClientUpload(t *testing.T) {
+	server := newUploadServer()
+	defer server.Close()
+
+	files, err := bdia.BuildFiles([]*datainsertion.Hit{newHit("1", 0), newHit("2", 1)}, bdia.Options{VisitorGroups: 8})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	results, err := newClient(t, server, false).UploadFiles(files)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(results) != len(files) || len(server.paths) != len(files) {
+		t.Fatalf("Expected %d uploads but got %d", len(files), len(server.paths))
+	}
+	for i, file := range files {
+		if server.paths[i] != "/aa/collect/v1/events" {
+			t.Errorf("Unexpected path %s", server.paths[i])
+		}
+		if server.vgids[i] != file.VisitorGroupID || results[i].VisitorGroupID != file.VisitorGroupID {
+			t.Errorf("Expected visitor group %s but got %s", file.VisitorGroupID, server.vgids[i])
+		}
+		if string(server.files[i]) != string(file.Data) || results[i].UploadName != file.Name || !results[i].Success {
+			t.Errorf("Unexpected upload of file %s: %+v", file.Name, results[i])
+		}
+	}
+}
+
+func TestClientValidateOnly(t *testing.T) {
+	server := newUploadServer()
+	defer server.Close()
+
+	files, err := bdia.BuildFiles([]*datainsertion.Hit{newHit("1", 0)}, bdia.Options{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	_, err = newClient(t, server, true).Upload(files[0])
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(server.paths) != 1 || server.paths[0] != "/aa/collect/v1/events/validate" {
+		t.Errorf("Expected validation but got %v", server.paths)
+	}
+
+	server.status = http.StatusBadRequest
+	_, err = newClient(t, server, false).Validate(files[0])
+	apiErr, ok := err.(*bdia.APIError)
+	if !ok || apiErr.StatusCode != http.StatusBadRequest || apiErr.ErrorCode != "invalid_file" || apiErr.Message != "invalid row" {
+		t.Errorf("Expected API error but got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package bdia
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/aa-client-go/datainsertion"
+)
+
+const (
+	// DefaultMaxFileSize is the default limit of the uncompressed size of a file
+	DefaultMaxFileSize = 100 << 20
+	// DefaultVisitorGroupID is the visitor group ID of files with a single visitor group
+	DefaultVisitorGroupID = "default"
+)
+
+// columns is the order of the known columns of files, eVars and props follow in numeric order
+var columns = []string{
+	"reportSuiteID", "timestamp", "visitorID", "marketingCloudVisitorID", "IPAddress", "userAgent",
+	"pageName", "pageURL", "referrer", "channel", "events", "products", "purchaseID",
+	"linkType", "linkName", "linkURL",
+}
+
+// Options configures the files built by BuildFiles.
+type Options struct {
+	// MaxFileSize limits the uncompressed size of a file in bytes, defaults to DefaultMaxFileSize.
+	// The compressed size of a file is smaller, the uncompressed size keeps files within the compressed size limit of BDIA.
+	MaxFileSize int
+	// VisitorGroups is the number of visitor groups the visitors are distributed to, defaults to 1.
+	// Files of different visitor groups are processed in parallel by BDIA.
+	// A visitor is always assigned to the same group as long as the number of groups does not change.
+	VisitorGroups int
+}
+
+// File represents a gzipped CSV file of hits of a visitor group
+type File struct {
+	Name           string
+	VisitorGroupID string
+	Rows           int
+	// Data is the gzipped CSV data
+	Data []byte
+}
+
+// ValidationError is returned for a hit which violates the BDIA rules.
+type ValidationError struct {
+	// Index is the index of the hit
+	Index int
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid hit %d: %v", e.Index, e.Err)
+}
+
+// Validate checks a hit against the BDIA rules.
+// In addition to the rules of the Data Insertion API, every hit requires a timestamp and a user agent.
+func Validate(hit *datainsertion.Hit) error {
+	if err := hit.Validate(); err != nil {
+		return err
+	}
+	if hit.Timestamp.IsZero() {
+		return fmt.Errorf("missing Timestamp")
+	}
+	if hit.UserAgent == "" {
+		return fmt.Errorf("missing UserAgent")
+	}
+	return nil
+}
+
+// VisitorGroupID returns the visitor group ID of a hit for the number of visitor groups.
+func VisitorGroupID(hit *datainsertion.Hit, visitorGroups int) string {
+	if visitorGroups <= 1 {
+		return DefaultVisitorGroupID
+	}
+	h := fnv.New32a()
+	h.Write([]byte(hit.VisitorID + "|" + hit.MarketingCloudVisitorID + "|" + hit.IPAddress))
+	return "vg" + strconv.Itoa(int(h.Sum32()%uint32(visitorGroups)))
+}
+
+// BuildFiles validates the hits and builds the files of their visitor groups.
+// The hits of a visitor group are ordered chronologically and split into files of at most Options.MaxFileSize bytes.
+// Files are ordered by visitor group ID and must be uploaded in this order.
+func BuildFiles(hits []*datainsertion.Hit, options Options) ([]*File, error) {
+	if options.MaxFileSize <= 0 {
+		options.MaxFileSize = DefaultMaxFileSize
+	}
+
+	groups := map[string][]*datainsertion.Hit{}
+	for i, hit := range hits {
+		if err := Validate(hit); err != nil {
+			return nil, &ValidationError{Index: i, Err: err}
+		}
+		id := VisitorGroupID(hit, options.VisitorGroups)
+		groups[id] = append(groups[id], hit)
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var files []*File
+	for _, id := range ids {
+		groupFiles, err := buildGroupFiles(id, groups[id], options.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, groupFiles...)
+	}
+	return files, nil
+}
+
+// buildGroupFiles builds the files of a visitor group, all files share the columns of the group
+func buildGroupFiles(id string, hits []*datainsertion.Hit, maxFileSize int) ([]*File, error) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Timestamp.Before(hits[j].Timestamp)
+	})
+
+	rows := make([]map[string]string, len(hits))
+	names := map[string]bool{}
+	for i, hit := range hits {
+		rows[i] = map[string]string{}
+		for _, field := range hit.Fields() {
+			rows[i][field.Name] = field.Value
+			names[field.Name] = true
+		}
+	}
+	header := sortColumns(names)
+	headerData := encodeRow(header)
+
+	var files []*File
+	var data bytes.Buffer
+	count := 0
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		file, err := newFile(fmt.Sprintf("%s-%d.csv.gz", id, len(files)+1), id, count, data.Bytes())
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		data.Reset()
+		count = 0
+		return nil
+	}
+
+	for _, row := range rows {
+		values := make([]string, len(header))
+		for i, name := range header {
+			values[i] = row[name]
+		}
+		rowData := encodeRow(values)
+		if len(headerData)+len(rowData) > maxFileSize {
+			return nil, fmt.Errorf("row of %d bytes exceeds the maximum file size", len(rowData))
+		}
+		if data.Len()+len(rowData) > maxFileSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		if data.Len() == 0 {
+			data.Write(headerData)
+		}
+		data.Write(rowData)
+		count++
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// newFile returns a file with the gzipped CSV data
+func newFile(name, visitorGroupID string, rows int, csvData []byte) (*File, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(csvData); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return &File{Name: name, VisitorGroupID: visitorGroupID, Rows: rows, Data: buf.Bytes()}, nil
+}
+
+// encodeRow returns a CSV row
+func encodeRow(values []string) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	// writing to a buffer does not fail
+	w.Write(values)
+	w.Flush()
+	return buf.Bytes()
+}
+
+// sortColumns returns the column names in the order of columns, followed by eVars and props in numeric order
+func sortColumns(names map[string]bool) []string {
+	var sorted []string
+	for _, name := range columns {
+		if names[name] {
+			sorted = append(sorted, name)
+		}
+	}
+	for _, prefix := range []string{"eVar", "prop"} {
+		var numbers []int
+		for name := range names {
+			if strings.HasPrefix(name, prefix) {
+				n, _ := strconv.Atoi(strings.TrimPrefix(name, prefix))
+				numbers = append(numbers, n)
+			}
+		}
+		sort.Ints(numbers)
+		for _, n := range numbers {
+			sorted = append(sorted, prefix+strconv.Itoa(n))
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package bdia_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/bdia"
+	"github.com/adobe/aa-client-go/datainsertion"
+)
+
+func newHit(visitorID string, minute int) *datainsertion.Hit {
+	return &datainsertion.Hit{
+		ReportSuiteID: "rsid",
+		VisitorID:     visitorID,
+		UserAgent:     "Mozilla/5.0",
+		PageName:      "page " + strconv.Itoa(minute),
+		Timestamp:     time.Date(2020, 4, 1, 10, minute, 0, 0, time.UTC),
+	}
+}
+
+// readFile returns the CSV records of a file
+func readFile(t *testing.T, file *bdia.File) [][]string {
+	gz, err := gzip.NewReader(bytes.NewReader(file.Data))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	records, err := csv.NewReader(gz).ReadAll()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return records
+}
+
+func TestValidate(t *testing.T) {
+	hit := newHit("1", 0)
+	if err := bdia.Validate(hit); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	hit.UserAgent = ""
+	if err := bdia.Validate(hit); err == nil || err.Error() != "missing UserAgent" {
+		t.Errorf("Expected missing UserAgent but got %v", err)
+	}
+
+	hit = newHit("1", 0)
+	hit.Timestamp = time.Time{}
+	if err := bdia.Validate(hit); err == nil || err.Error() != "missing Timestamp" {
+		t.Errorf("Expected missing Timestamp but got %v", err)
+	}
+
+	hit = newHit("", 0)
+	_, err := bdia.BuildFiles([]*datainsertion.Hit{newHit("1", 0), hit}, bdia.Options{})
+	var validationErr *bdia.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Index != 1 {
+		t.Errorf("Expected validation error of hit 1 but got %v", err)
+	}
+}
+
+func TestBuildFiles(t *testing.T) {
+	hits := []*datainsertion.Hit{newHit("1", 2), newHit("2", 1), newHit("1", 0)}
+	hits[0].EVars = map[int]string{10: "ten", 2: "two, quoted"}
+	hits[1].Props = map[int]string{1: "one"}
+	hits[1].Events = []string{"purchase", "event1=2"}
+
+	files, err := bdia.BuildFiles(hits, bdia.Options{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected %d files but got %d", 1, len(files))
+	}
+	file := files[0]
+	if file.Name != "default-1.csv.gz" || file.VisitorGroupID != bdia.DefaultVisitorGroupID || file.Rows != 3 {
+		t.Errorf("Unexpected file %s of visitor group %s with %d rows", file.Name, file.VisitorGroupID, file.Rows)
+	}
+
+	records := readFile(t, file)
+	expected := [][]string{
+		{"reportSuiteID", "timestamp", "visitorID", "userAgent", "pageName", "events", "eVar2", "eVar10", "prop1"},
+		{"rsid", "1585735200", "1", "Mozilla/5.0", "page 0", "", "", "", ""},
+		{"rsid", "1585735260", "2", "Mozilla/5.0", "page 1", "purchase,event1=2", "", "", "one"},
+		{"rsid", "1585735320", "1", "Mozilla/5.0", "page 2", "", "two, quoted", "ten", ""},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records but got %d", len(expected), len(records))
+	}
+	for i := range expected {
+		if len(records[i]) != len(expected[i]) {
+			t.Fatalf("Expected record %v but got %v", expected[i], records[i])
+		}
+		for j := range expected[i] {
+			if records[i][j] != expected[i][j] {
+				t.Errorf("Expected record %v but got %v", expected[i], records[i])
+				break
+			}
+		}
+	}
+}
+
+func TestBuildFilesSplit(t *testing.T) {
+	var hits []*datainsertion.Hit
+	for i := 0; i < 40; i++ {
+		hits = append(hits, newHit(strconv.Itoa(i%8), i))
+	}
+
+	files, err := bdia.BuildFiles(hits, bdia.Options{MaxFileSize: 500, VisitorGroups: 3})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	rows := 0
+	groups := map[string]string{}
+	for i, file := range files {
+		if i > 0 && files[i-1].VisitorGroupID > file.VisitorGroupID {
+			t.Errorf("Expected files ordered by visitor group")
+		}
+		records := readFile(t, file)
+		if len(records)-1 != file.Rows {
+			t.Errorf("Expected %d rows but got %d", file.Rows, len(records)-1)
+		}
+		for _, record := range records[1:] {
+			visitorID := record[2]
+			if group, ok := groups[visitorID]; ok && group != file.VisitorGroupID {
+				t.Errorf("Visitor %s in visitor groups %s and %s", visitorID, group, file.VisitorGroupID)
+			}
+			groups[visitorID] = file.VisitorGroupID
+		}
+		rows += file.Rows
+	}
+	if rows != len(hits) {
+		t.Errorf("Expected %d rows but got %d", len(hits), rows)
+	}
+	if len(files) < 4 {
+		t.Errorf("Expected files to be split but got %d files", len(files))
+	}
+
+	_, err = bdia.BuildFiles(hits, bdia.Options{MaxFileSize: 50})
+	if err == nil {
+		t.Errorf("Expected error for rows exceeding the maximum file size")
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package datafeed parses Adobe Analytics data feeds.
+// A data feed consists of the hit data (hit_data.tsv), the column headers (column_headers.tsv)
+// and lookup files like browser.tsv or event.tsv.
+// Data feed docs: https://experienceleague.adobe.com/docs/analytics/export/analytics-data-feed/data-feed-overview.html
+package datafeed
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LookupColumns maps columns to the lookup files resolving their values, without the .tsv extension.
+var LookupColumns = map[string]string{
+	"browser":            "browser",
+	"color":              "color_depth",
+	"connection_type":    "connection_type",
+	"country":            "country",
+	"event_list":         "event",
+	"javascript":         "javascript_version",
+	"language":           "languages",
+	"os":                 "operating_systems",
+	"plugins":            "plugins",
+	"ref_type":           "referrer_type",
+	"resolution":         "resolution",
+	"search_engine":      "search_engines",
+	"post_event_list":    "event",
+	"post_search_engine": "search_engines",
+}
+
+// excludedHitSources are the hit sources of hits which are not part of reports
+var excludedHitSources = map[string]bool{"5": true, "7": true, "8": true, "9": true}
+
+// Options configures a Reader.
+type Options struct {
+	// PostColumns selects the post_ column of a column by Record.Get, if present, e.g. post_evar1 for evar1.
+	PostColumns bool
+	// IncludeExcluded includes hits which are excluded from reports, see Record.Excluded.
+	IncludeExcluded bool
+}
+
+// Lookups holds the lookup tables of a data feed by name, e.g. "browser" for browser.tsv.
+type Lookups map[string]map[string]string
+
+// ReadLookup reads a lookup file with ID and value columns.
+func ReadLookup(r io.Reader) (map[string]string, error) {
+	tsv := newTSVReader(r)
+	table := map[string]string{}
+	for {
+		row, err := tsv.readRow()
+		if err == io.EOF {
+			return table, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) < 2 {
+			continue
+		}
+		table[row[0]] = row[1]
+	}
+}
+
+// ReadColumnHeaders reads the column names of the hit data from a column_headers.tsv file.
+func ReadColumnHeaders(r io.Reader) ([]string, error) {
+	row, err := newTSVReader(r).readRow()
+	if err == io.EOF {
+		return nil, fmt.Errorf("missing column headers")
+	}
+	return row, err
+}
+
+// Reader streams the hits of hit data.
+type Reader struct {
+	tsv     *tsvReader
+	columns map[string]int
+	lookups Lookups
+	options Options
+	line    int
+}
+
+// NewReader returns a new Reader of the hit data with the columns and the lookups, which may be nil.
+func NewReader(hitData io.Reader, columns []string, lookups Lookups, options Options) *Reader {
+	index := map[string]int{}
+	for i, column := range columns {
+		index[column] = i
+	}
+	if lookups == nil {
+		lookups = Lookups{}
+	}
+	return &Reader{
+		tsv:     newTSVReader(hitData),
+		columns: index,
+		lookups: lookups,
+		options: options,
+	}
+}
+
+// Read returns the next hit or io.EOF.
+func (r *Reader) Read() (*Record, error) {
+	for {
+		values, err := r.tsv.readRow()
+		if err != nil {
+			return nil, err
+		}
+		r.line++
+		if len(values) != len(r.columns) {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", r.line, len(values), len(r.columns))
+		}
+
+		record := &Record{reader: r, values: values}
+		if record.Excluded() && !r.options.IncludeExcluded {
+			continue
+		}
+		return record, nil
+	}
+}
+
+// Event represents an event of a hit
+type Event struct {
+	ID    string
+	Name  string
+	Value float64
+}
+
+// Record represents a hit of the hit data.
+type Record struct {
+	reader *Reader
+	values []string
+}
+
+// Raw returns the value of the column or an empty string if the column doesn't exist.
+func (r *Record) Raw(column string) string {
+	i, ok := r.reader.columns[column]
+	if !ok {
+		return ""
+	}
+	return r.values[i]
+}
+
+// Get returns the value of the column, or of its post_ column if selected by the options.
+func (r *Record) Get(column string) string {
+	if r.reader.options.PostColumns && !strings.HasPrefix(column, "post_") {
+		if _, ok := r.reader.columns["post_"+column]; ok {
+			return r.Raw("post_" + column)
+		}
+	}
+	return r.Raw(column)
+}
+
+// Lookup returns the value of the column resolved with its lookup file, see LookupColumns.
+// Lists like plugins are resolved per item and joined with commas. Values without lookup are returned as is.
+func (r *Record) Lookup(column string) string {
+	value := r.Get(column)
+	name, ok := LookupColumns[column]
+	if r.reader.options.PostColumns {
+		if postName, postOk := LookupColumns["post_"+column]; postOk {
+			name, ok = postName, true
+		}
+	}
+	table := r.reader.lookups[name]
+	if !ok || table == nil || value == "" {
+		return value
+	}
+
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		if resolved, ok := table[item]; ok {
+			items[i] = resolved
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// Int returns the value of the column as integer, 0 if it is empty or malformed.
+func (r *Record) Int(column string) int64 {
+	value, _ := strconv.ParseInt(r.Get(column), 10, 64)
+	return value
+}
+
+// VisitorID returns the visitor ID made of post_visid_high and post_visid_low.
+func (r *Record) VisitorID() string {
+	return r.Raw("post_visid_high") + ":" + r.Raw("post_visid_low")
+}
+
+// VisitID returns the visit ID made of the visitor ID and visit_num.
+func (r *Record) VisitID() string {
+	return r.VisitorID() + ":" + r.Raw("visit_num")
+}
+
+// Time returns the hit time of hit_time_gmt.
+func (r *Record) Time() time.Time {
+	seconds, _ := strconv.ParseInt(r.Raw("hit_time_gmt"), 10, 64)
+	return time.Unix(seconds, 0).UTC()
+}
+
+// PageView returns true if the hit is a page view, i.e. the page_event is 0.
+func (r *Record) PageView() bool {
+	return r.Get("page_event") == "0"
+}
+
+// Excluded returns true if the hit is excluded from reports, by exclude_hit or by hit_source.
+func (r *Record) Excluded() bool {
+	excludeHit := r.Raw("exclude_hit")
+	return (excludeHit != "" && excludeHit != "0") || excludedHitSources[r.Raw("hit_source")]
+}
+
+// Events returns the events of the event_list, or of the post_event_list if selected by the options.
+// Names are resolved with the event lookup file, counter events have the value 1.
+func (r *Record) Events() []Event {
+	list := r.Get("event_list")
+	if list == "" {
+		return nil
+	}
+
+	table := r.reader.lookups["event"]
+	var events []Event
+	for _, item := range strings.Split(list, ",") {
+		event := Event{Value: 1}
+		if i := strings.Index(item, "="); i >= 0 {
+			event.Value, _ = strconv.ParseFloat(item[i+1:], 64)
+			item = item[:i]
+		}
+		// strip the event serialization ID, e.g. "201:abc"
+		if i := strings.Index(item, ":"); i >= 0 {
+			item = item[:i]
+		}
+		event.ID = strings.TrimSpace(item)
+		event.Name = table[event.ID]
+		events = append(events, event)
+	}
+	return events
+}
+
+// Map returns the values by column.
+func (r *Record) Map() map[string]string {
+	m := make(map[string]string, len(r.values))
+	for column, i := range r.reader.columns {
+		m[column] = r.values[i]
+	}
+	return m
+}
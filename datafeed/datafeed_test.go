@@ -0,0 +1,190 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datafeed_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/datafeed"
+)
+
+func readAll(t *testing.T, feed *datafeed.Feed) []*datafeed.Record {
+	var records []*datafeed.Record
+	for {
+		record, err := feed.Read()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestOpenDir(t *testing.T) {
+	feed, err := datafeed.OpenDir("./testdata/feed", datafeed.Options{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer feed.Close()
+
+	records := readAll(t, feed)
+	if len(records) != 3 {
+		t.Fatalf("Expected %d records but got %d", 3, len(records))
+	}
+
+	first := records[0]
+	if first.Get("evar1") != "Home\tPage" {
+		t.Errorf("Expected evar1 %q but got %q", "Home\tPage", first.Get("evar1"))
+	}
+	if first.Lookup("browser") != "Google Chrome 80" {
+		t.Errorf("Expected browser %q but got %q", "Google Chrome 80", first.Lookup("browser"))
+	}
+	if first.Lookup("plugins") != "Java,PDF Reader" {
+		t.Errorf("Expected plugins %q but got %q", "Java,PDF Reader", first.Lookup("plugins"))
+	}
+	if first.VisitorID() != "123:456" || first.VisitID() != "123:456:1" {
+		t.Errorf("Unexpected visitor %q and visit %q", first.VisitorID(), first.VisitID())
+	}
+	if !first.Time().Equal(time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected time %v", first.Time())
+	}
+	if !first.PageView() || first.Int("visit_num") != 1 {
+		t.Errorf("Expected page view of visit 1")
+	}
+	expected := []datafeed.Event{
+		{ID: "1", Name: "Purchase", Value: 1},
+		{ID: "200", Name: "Instance of eVar1", Value: 1},
+	}
+	if !reflect.DeepEqual(first.Events(), expected) {
+		t.Errorf("Expected events %v but got %v", expected, first.Events())
+	}
+
+	second := records[1]
+	if second.Get("evar1") != "line1\nline2" || second.PageView() {
+		t.Errorf("Unexpected record %v", second.Map())
+	}
+}
+
+func TestOpenDirOptions(t *testing.T) {
+	feed, err := datafeed.OpenDir("./testdata/feed", datafeed.Options{PostColumns: true, IncludeExcluded: true})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer feed.Close()
+
+	records := readAll(t, feed)
+	if len(records) != 5 {
+		t.Fatalf("Expected %d records but got %d", 5, len(records))
+	}
+
+	if records[0].Get("evar1") != "home\tpage" {
+		t.Errorf("Expected post_evar1 %q but got %q", "home\tpage", records[0].Get("evar1"))
+	}
+	events := records[0].Events()
+	if len(events) != 3 || events[2].Name != "Custom Event 1" || events[2].Value != 5 {
+		t.Errorf("Unexpected post events %v", events)
+	}
+
+	if !records[2].Excluded() || !records[3].Excluded() || records[4].Excluded() {
+		t.Errorf("Unexpected excluded hits")
+	}
+	if records[3].Get("page_url") != `https://example.com/back\slash` {
+		t.Errorf("Unexpected page URL %q", records[3].Get("page_url"))
+	}
+	expected := []datafeed.Event{{ID: "20101", Name: "Custom Event 2", Value: 2.5}}
+	if !reflect.DeepEqual(records[3].Events(), expected) {
+		t.Errorf("Expected events %v but got %v", expected, records[3].Events())
+	}
+	if records[3].Lookup("browser") != "999" {
+		t.Errorf("Expected unresolved browser %q but got %q", "999", records[3].Lookup("browser"))
+	}
+}
+
+// writeTarGz writes the files to a tar.gz archive
+func writeTarGz(t *testing.T, w io.Writer, files map[string][]byte) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func TestOpenBundle(t *testing.T) {
+	files := map[string][]byte{}
+	for _, name := range []string{"hit_data.tsv", "column_headers.tsv", "browser.tsv", "event.tsv"} {
+		data, err := ioutil.ReadFile(filepath.Join("./testdata/feed", name))
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		files[name] = data
+	}
+
+	var lookupData bytes.Buffer
+	writeTarGz(t, &lookupData, map[string][]byte{"browser.tsv": files["browser.tsv"], "event.tsv": files["event.tsv"]})
+
+	dir, err := ioutil.TempDir("", "aa-client-go-datafeed")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle, err := os.Create(filepath.Join(dir, "feed_2020-04-01.tar.gz"))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	writeTarGz(t, bundle, map[string][]byte{
+		"hit_data.tsv":                       files["hit_data.tsv"],
+		"column_headers.tsv":                 files["column_headers.tsv"],
+		"feed_2020-04-01-lookup_data.tar.gz": lookupData.Bytes(),
+	})
+	bundle.Close()
+
+	feed, err := datafeed.OpenBundle(bundle.Name(), datafeed.Options{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer feed.Close()
+
+	records := readAll(t, feed)
+	if len(records) != 3 {
+		t.Fatalf("Expected %d records but got %d", 3, len(records))
+	}
+	if records[0].Lookup("browser") != "Google Chrome 80" || records[0].Events()[0].Name != "Purchase" {
+		t.Errorf("Expected lookups to be resolved")
+	}
+}
+
+func TestReaderColumnMismatch(t *testing.T) {
+	reader := datafeed.NewReader(strings.NewReader("1\t2\t3\n"), []string{"a", "b"}, nil, datafeed.Options{})
+	_, err := reader.Read()
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datafeed
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// File names of a data feed
+const (
+	HitDataFile       = "hit_data.tsv"
+	ColumnHeadersFile = "column_headers.tsv"
+)
+
+// Feed is a Reader of a data feed in a directory or bundle, it must be closed when finished.
+type Feed struct {
+	*Reader
+	Columns []string
+	Lookups Lookups
+
+	closers []io.Closer
+}
+
+// Close closes the files of the feed.
+func (f *Feed) Close() error {
+	var err error
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		if closeErr := f.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// OpenDir opens the data feed of a directory holding hit_data.tsv, column_headers.tsv and the lookup files.
+func OpenDir(dir string, options Options) (*Feed, error) {
+	columnHeaders, err := os.Open(filepath.Join(dir, ColumnHeadersFile))
+	if err != nil {
+		return nil, err
+	}
+	columns, err := ReadColumnHeaders(columnHeaders)
+	columnHeaders.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tsv"))
+	if err != nil {
+		return nil, err
+	}
+	lookups := Lookups{}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if name == HitDataFile || name == ColumnHeadersFile {
+			continue
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		table, err := ReadLookup(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("malformed lookup file %s: %v", name, err)
+		}
+		lookups[strings.TrimSuffix(name, ".tsv")] = table
+	}
+
+	hitData, err := os.Open(filepath.Join(dir, HitDataFile))
+	if err != nil {
+		return nil, err
+	}
+	return &Feed{
+		Reader:  NewReader(hitData, columns, lookups, options),
+		Columns: columns,
+		Lookups: lookups,
+		closers: []io.Closer{hitData},
+	}, nil
+}
+
+// OpenBundle opens the data feed of a tar.gz bundle holding hit_data.tsv, column_headers.tsv and the lookup files.
+// Lookup files may also be bundled in a nested lookup_data tar.gz.
+// The bundle is read twice, first for the column headers and lookups, then to stream the hit data.
+func OpenBundle(file string, options Options) (*Feed, error) {
+	var columns []string
+	lookups := Lookups{}
+	err := walkBundle(file, func(name string, r io.Reader) error {
+		var err error
+		switch {
+		case name == HitDataFile:
+		case name == ColumnHeadersFile:
+			columns, err = ReadColumnHeaders(r)
+		case strings.HasSuffix(name, ".tsv"):
+			lookups[strings.TrimSuffix(name, ".tsv")], err = ReadLookup(r)
+		case strings.HasSuffix(name, ".tar.gz"):
+			// nested lookup data
+			err = walkTarGz(r, func(name string, r io.Reader) error {
+				if !strings.HasSuffix(name, ".tsv") || name == HitDataFile {
+					return nil
+				}
+				table, err := ReadLookup(r)
+				lookups[strings.TrimSuffix(name, ".tsv")] = table
+				return err
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("missing %s in %s", ColumnHeadersFile, file)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			gz.Close()
+			f.Close()
+			return nil, fmt.Errorf("missing %s in %s", HitDataFile, file)
+		}
+		if err != nil {
+			gz.Close()
+			f.Close()
+			return nil, err
+		}
+		if path.Base(header.Name) == HitDataFile {
+			return &Feed{
+				Reader:  NewReader(tr, columns, lookups, options),
+				Columns: columns,
+				Lookups: lookups,
+				closers: []io.Closer{f, gz},
+			}, nil
+		}
+	}
+}
+
+// walkBundle calls fn for each file of a tar.gz bundle until it returns an error.
+func walkBundle(file string, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return walkTarGz(f, fn)
+}
+
+// walkTarGz calls fn for each file of a tar.gz stream until it returns an error.
+func walkTarGz(r io.Reader, fn func(name string, r io.Reader) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		err = fn(path.Base(header.Name), tr)
+		if err != nil {
+			return fmt.Errorf("%s: %v", header.Name, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datafeed
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// tsvReader reads rows of data feed files.
+// Tabs, newlines and backslashes in values are escaped with a backslash.
+type tsvReader struct {
+	r *bufio.Reader
+}
+
+// newTSVReader returns a new tsvReader.
+func newTSVReader(r io.Reader) *tsvReader {
+	return &tsvReader{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// readRow returns the values of the next row or io.EOF.
+func (t *tsvReader) readRow() ([]string, error) {
+	var (
+		values []string
+		value  bytes.Buffer
+		read   bool
+	)
+	for {
+		b, err := t.r.ReadByte()
+		if err == io.EOF {
+			if !read {
+				return nil, io.EOF
+			}
+			// last row without newline
+			return append(values, value.String()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		read = true
+
+		switch b {
+		case '\\':
+			escaped, err := t.r.ReadByte()
+			if err == io.EOF {
+				value.WriteByte(b)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			value.WriteByte(escaped)
+		case '\t':
+			values = append(values, value.String())
+			value.Reset()
+		case '\n':
+			values = append(values, strings.TrimSuffix(value.String(), "\r"))
+			return values, nil
+		default:
+			value.WriteByte(b)
+		}
+	}
+}
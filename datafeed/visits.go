@@ -0,0 +1,212 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datafeed
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// Visit represents a visit reconstructed from hits
+type Visit struct {
+	VisitorID string
+	VisitNum  int64
+	// Hits are ordered by visit_page_num and hit_time_gmt
+	Hits []*Record
+}
+
+// Start returns the time of the first hit.
+func (v *Visit) Start() time.Time {
+	return v.Hits[0].Time()
+}
+
+// End returns the time of the last hit.
+func (v *Visit) End() time.Time {
+	return v.Hits[len(v.Hits)-1].Time()
+}
+
+// Duration returns the time spent, i.e. the time between the first and the last hit.
+func (v *Visit) Duration() time.Duration {
+	return v.End().Sub(v.Start())
+}
+
+// PageViews returns the number of page views.
+func (v *Visit) PageViews() int {
+	pageViews := 0
+	for _, hit := range v.Hits {
+		if hit.PageView() {
+			pageViews++
+		}
+	}
+	return pageViews
+}
+
+// EntryPage returns the page of the first page view, see Page.
+func (v *Visit) EntryPage() string {
+	for _, hit := range v.Hits {
+		if hit.PageView() {
+			return Page(hit)
+		}
+	}
+	return ""
+}
+
+// ExitPage returns the page of the last page view, see Page.
+func (v *Visit) ExitPage() string {
+	for i := len(v.Hits) - 1; i >= 0; i-- {
+		if v.Hits[i].PageView() {
+			return Page(v.Hits[i])
+		}
+	}
+	return ""
+}
+
+// Bounce returns true if the visit has a single hit.
+func (v *Visit) Bounce() bool {
+	return len(v.Hits) == 1
+}
+
+// Visitor represents a visitor with visits ordered by visit number
+type Visitor struct {
+	ID     string
+	Visits []*Visit
+}
+
+// Totals represents the totals of visits, as defined by the reports API
+type Totals struct {
+	Hits      int
+	PageViews int
+	Visits    int
+	Visitors  int
+	Bounces   int
+	TimeSpent time.Duration
+}
+
+// BounceRate returns the bounces per visit.
+func (t Totals) BounceRate() float64 {
+	if t.Visits == 0 {
+		return 0
+	}
+	return float64(t.Bounces) / float64(t.Visits)
+}
+
+// Page returns the page name of a hit, or the page URL if it has no page name.
+func Page(hit *Record) string {
+	if page := hit.Get("pagename"); page != "" {
+		return page
+	}
+	return hit.Get("page_url")
+}
+
+// visitKey identifies a visit
+type visitKey struct {
+	visitorID string
+	visitNum  int64
+}
+
+// Visits groups hits into visits by visitor ID and visit_num.
+// Hits which are excluded from reports should be skipped, see Options.IncludeExcluded.
+type Visits struct {
+	visits map[visitKey]*Visit
+}
+
+// NewVisits returns new, empty Visits.
+func NewVisits() *Visits {
+	return &Visits{visits: map[visitKey]*Visit{}}
+}
+
+// ReadVisits reads all hits of the reader and returns their visits.
+func ReadVisits(r *Reader) (*Visits, error) {
+	visits := NewVisits()
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return visits, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		visits.Add(record)
+	}
+}
+
+// Add adds a hit to its visit.
+func (v *Visits) Add(hit *Record) {
+	key := visitKey{visitorID: hit.VisitorID(), visitNum: hit.Int("visit_num")}
+	visit, ok := v.visits[key]
+	if !ok {
+		visit = &Visit{VisitorID: key.visitorID, VisitNum: key.visitNum}
+		v.visits[key] = visit
+	}
+	// insert the hit after the hits ordered before or equal to it, the hits stay ordered
+	i := sort.Search(len(visit.Hits), func(i int) bool {
+		return hitBefore(hit, visit.Hits[i])
+	})
+	visit.Hits = append(visit.Hits, nil)
+	copy(visit.Hits[i+1:], visit.Hits[i:])
+	visit.Hits[i] = hit
+}
+
+// hitBefore returns true if hit a is ordered before hit b by visit_page_num and hit_time_gmt
+func hitBefore(a, b *Record) bool {
+	if pa, pb := a.Int("visit_page_num"), b.Int("visit_page_num"); pa != pb {
+		return pa < pb
+	}
+	return a.Int("hit_time_gmt") < b.Int("hit_time_gmt")
+}
+
+// Visits returns the visits ordered by visitor ID and visit number.
+func (v *Visits) Visits() []*Visit {
+	visits := make([]*Visit, 0, len(v.visits))
+	for _, visit := range v.visits {
+		visits = append(visits, visit)
+	}
+	sort.Slice(visits, func(i, j int) bool {
+		if visits[i].VisitorID != visits[j].VisitorID {
+			return visits[i].VisitorID < visits[j].VisitorID
+		}
+		return visits[i].VisitNum < visits[j].VisitNum
+	})
+	return visits
+}
+
+// Visitors returns the visitors ordered by ID.
+func (v *Visits) Visitors() []*Visitor {
+	var visitors []*Visitor
+	for _, visit := range v.Visits() {
+		if len(visitors) == 0 || visitors[len(visitors)-1].ID != visit.VisitorID {
+			visitors = append(visitors, &Visitor{ID: visit.VisitorID})
+		}
+		visitor := visitors[len(visitors)-1]
+		visitor.Visits = append(visitor.Visits, visit)
+	}
+	return visitors
+}
+
+// Totals returns the totals of the visits.
+func (v *Visits) Totals() Totals {
+	var totals Totals
+	visitors := map[string]bool{}
+	for _, visit := range v.visits {
+		totals.Hits += len(visit.Hits)
+		totals.PageViews += visit.PageViews()
+		totals.Visits++
+		if visit.Bounce() {
+			totals.Bounces++
+		}
+		totals.TimeSpent += visit.Duration()
+		visitors[visit.VisitorID] = true
+	}
+	totals.Visitors = len(visitors)
+	return totals
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datafeed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/datafeed"
+)
+
+var (
+	visitsColumns = []string{"hit_time_gmt", "post_visid_high", "post_visid_low", "visit_num", "visit_page_num", "page_event", "pagename", "page_url", "exclude_hit", "hit_source"}
+	visitsHitData = strings.Join([]string{
+		"1585735260\t1\t2\t1\t2\t0\tProducts\thttps://example.com/products\t0\t1",
+		"1585735200\t1\t2\t1\t1\t0\tHome\thttps://example.com/\t0\t1",
+		"1585735290\t1\t2\t1\t3\t100\t\thttps://example.com/products\t0\t1",
+		"1585735300\t1\t2\t1\t4\t0\t\thttps://example.com/excluded\t1\t1",
+		"1585821600\t1\t2\t2\t1\t0\t\thttps://example.com/\t0\t1",
+		"1585735200\t3\t4\t1\t1\t0\tHome\thttps://example.com/\t0\t1",
+	}, "\n") + "\n"
+)
+
+func TestReadVisits(t *testing.T) {
+	visits, err := datafeed.ReadVisits(datafeed.NewReader(strings.NewReader(visitsHitData), visitsColumns, nil, datafeed.Options{}))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	list := visits.Visits()
+	if len(list) != 3 {
+		t.Fatalf("Expected %d visits but got %d", 3, len(list))
+	}
+	first := list[0]
+	if first.VisitorID != "1:2" || first.VisitNum != 1 || len(first.Hits) != 3 {
+		t.Fatalf("Unexpected visit %v %v with %d hits", first.VisitorID, first.VisitNum, len(first.Hits))
+	}
+	if first.EntryPage() != "Home" || first.ExitPage() != "Products" {
+		t.Errorf("Unexpected entry page %q and exit page %q", first.EntryPage(), first.ExitPage())
+	}
+	if first.Duration() != 90*time.Second {
+		t.Errorf("Expected duration %v but got %v", 90*time.Second, first.Duration())
+	}
+	if first.PageViews() != 2 || first.Bounce() {
+		t.Errorf("Unexpected page views %d and bounce %v", first.PageViews(), first.Bounce())
+	}
+	if list[1].EntryPage() != "https://example.com/" || !list[1].Bounce() {
+		t.Errorf("Unexpected entry page %q and bounce %v", list[1].EntryPage(), list[1].Bounce())
+	}
+
+	visitors := visits.Visitors()
+	if len(visitors) != 2 || len(visitors[0].Visits) != 2 || visitors[1].ID != "3:4" {
+		t.Errorf("Unexpected visitors %v", visitors)
+	}
+
+	expected := datafeed.Totals{Hits: 5, PageViews: 4, Visits: 3, Visitors: 2, Bounces: 2, TimeSpent: 90 * time.Second}
+	totals := visits.Totals()
+	if totals != expected {
+		t.Errorf("Expected totals %+v but got %+v", expected, totals)
+	}
+	if totals.BounceRate() != 2.0/3.0 {
+		t.Errorf("Expected bounce rate %v but got %v", 2.0/3.0, totals.BounceRate())
+	}
+}
+
+func TestVisitsTotalsBeforeVisits(t *testing.T) {
+	visits, err := datafeed.ReadVisits(datafeed.NewReader(strings.NewReader(visitsHitData), visitsColumns, nil, datafeed.Options{}))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// the hits are ordered without calling Visits first
+	totals := visits.Totals()
+	if totals.TimeSpent != 90*time.Second {
+		t.Errorf("Expected time spent %v but got %v", 90*time.Second, totals.TimeSpent)
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package datainsertion sends hits to the Adobe Analytics Data Insertion API, e.g. offline conversions.
+// Hits are sent as XML POST or GET requests to the tracking server of a report suite.
+// Data Insertion API docs: https://github.com/AdobeDocs/analytics-1.4-apis/blob/master/docs/data-insertion-api/index.md
+package datainsertion
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryWait = time.Second
+	maxRetryWait     = time.Minute
+	statusSuccess    = "SUCCESS"
+)
+
+// Config holds configuration values
+type Config struct {
+	HTTPClient *http.Client
+	// BaseURL is the URL of the tracking server, e.g. "https://<namespace>.sc.omtrdc.net".
+	BaseURL string
+
+	// MaxRetries is the number of retries of hits which failed with status code 429 or 5xx.
+	MaxRetries int
+	// RetryNetworkErrors also retries hits which failed with a network error.
+	// The hit may have been received before the error, retrying it can count it twice.
+	RetryNetworkErrors bool
+	// RetryWait is the wait before the first retry, it doubles with each retry up to 1 minute and defaults to 1 second.
+	// A Retry-After header of the response takes precedence.
+	RetryWait time.Duration
+
+	// Concurrency is the number of visitors whose hits are sent in parallel by SendBatch, defaults to 1.
+	Concurrency int
+}
+
+// FailureError is returned if the Data Insertion API rejected a hit.
+type FailureError struct {
+	Status string
+	Reason string
+}
+
+func (e *FailureError) Error() string {
+	return fmt.Sprintf("data insertion failed with status %s: %s", e.Status, e.Reason)
+}
+
+// Result represents the result of sending a hit of a batch
+type Result struct {
+	Hit *Hit
+	Err error
+}
+
+// Client sends hits to the Data Insertion API.
+// It is safe for concurrent use.
+type Client struct {
+	httpClient   *http.Client
+	baseURL      *url.URL
+	maxRetries   int
+	retryNetwork bool
+	retryWait    time.Duration
+	concurrency  int
+	ctx          context.Context
+}
+
+// NewClient returns a new Data Insertion API client.
+func NewClient(config *Config) (*Client, error) {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	baseURL, err := url.Parse(strings.TrimSuffix(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("malformed URL")
+	}
+	if baseURL.Scheme == "" {
+		return nil, fmt.Errorf("missing URL scheme")
+	}
+	if baseURL.Host == "" {
+		return nil, fmt.Errorf("missing URL host")
+	}
+	if config.MaxRetries < 0 {
+		return nil, fmt.Errorf("negative MaxRetries")
+	}
+
+	c := &Client{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		maxRetries:   config.MaxRetries,
+		retryNetwork: config.RetryNetworkErrors,
+		retryWait:    config.RetryWait,
+		concurrency:  config.Concurrency,
+	}
+	if c.retryWait <= 0 {
+		c.retryWait = defaultRetryWait
+	}
+	if c.concurrency <= 0 {
+		c.concurrency = 1
+	}
+	return c, nil
+}
+
+// WithContext returns a copy of the client which sends its requests with ctx, e.g. to cancel retries.
+func (client *Client) WithContext(ctx context.Context) *Client {
+	c := *client
+	c.ctx = ctx
+	return &c
+}
+
+// context returns the context of the client's requests.
+func (client *Client) context() context.Context {
+	if client.ctx == nil {
+		return context.Background()
+	}
+	return client.ctx
+}
+
+// Send validates the hit and sends it as XML POST request.
+func (client *Client) Send(hit *Hit) error {
+	if err := hit.Validate(); err != nil {
+		return err
+	}
+	body, err := hit.XML()
+	if err != nil {
+		return err
+	}
+
+	return client.retry(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(client.context(), http.MethodPost, client.url("/b/ss//6"), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/xml")
+		return req, nil
+	}, checkXMLResponse)
+}
+
+// SendGet validates the hit and sends it as GET request.
+// IPAddress and UserAgent are sent as X-Forwarded-For and User-Agent headers.
+func (client *Client) SendGet(hit *Hit) error {
+	if err := hit.Validate(); err != nil {
+		return err
+	}
+
+	u := client.url("/b/ss/"+url.PathEscape(hit.ReportSuiteID)+"/0") + "?" + hit.Query().Encode()
+	return client.retry(func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(client.context(), http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if hit.IPAddress != "" {
+			req.Header.Set("X-Forwarded-For", hit.IPAddress)
+		}
+		if hit.UserAgent != "" {
+			req.Header.Set("User-Agent", hit.UserAgent)
+		}
+		return req, nil
+	}, func(io.Reader) error { return nil })
+}
+
+// SendBatch sends the hits with Send and returns the results in the order of the hits.
+// The hits of a visitor are sent one after another in their order, since the API processes hits in the order received.
+// Hits of different visitors are sent in parallel, see Config.Concurrency.
+func (client *Client) SendBatch(hits []*Hit) []Result {
+	results := make([]Result, len(hits))
+
+	var visitors [][]int
+	visitorIndex := map[string]int{}
+	for i, hit := range hits {
+		results[i].Hit = hit
+		key := hit.VisitorID + "|" + hit.MarketingCloudVisitorID + "|" + hit.IPAddress
+		index, ok := visitorIndex[key]
+		if !ok {
+			index = len(visitors)
+			visitorIndex[key] = index
+			visitors = append(visitors, nil)
+		}
+		visitors[index] = append(visitors[index], i)
+	}
+
+	queue := make(chan []int)
+	var wg sync.WaitGroup
+	for w := 0; w < client.concurrency && w < len(visitors); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for indexes := range queue {
+				for _, i := range indexes {
+					results[i].Err = client.Send(hits[i])
+				}
+			}
+		}()
+	}
+	for _, indexes := range visitors {
+		queue <- indexes
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// url returns the URL of the path on the tracking server
+func (client *Client) url(path string) string {
+	return client.baseURL.String() + path
+}
+
+// retry sends the requests returned by newRequest until it succeeds or the retries are exhausted.
+// The body of successful responses is checked by check.
+func (client *Client) retry(newRequest func() (*http.Request, error), check func(io.Reader) error) error {
+	ctx := client.context()
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		wait := client.backoff(attempt)
+		res, err := client.httpClient.Do(req)
+		if err != nil && !client.retryNetwork {
+			return err
+		}
+		if err == nil {
+			if c := res.StatusCode; 200 <= c && c <= 299 {
+				err = check(res.Body)
+				res.Body.Close()
+				return err
+			}
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+
+			err = fmt.Errorf("received unexpected status code %d", res.StatusCode)
+			if res.StatusCode != http.StatusTooManyRequests && res.StatusCode < 500 {
+				return err
+			}
+			if seconds, parseErr := strconv.Atoi(res.Header.Get("Retry-After")); parseErr == nil && seconds >= 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+		}
+		if attempt >= client.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the retry after attempt, the doubled wait is capped at maxRetryWait.
+func (client *Client) backoff(attempt int) time.Duration {
+	wait := client.retryWait
+	for i := 0; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryWait && wait > client.retryWait {
+		wait = maxRetryWait
+	}
+	return wait
+}
+
+// xmlResponse represents an XML response of the Data Insertion API
+type xmlResponse struct {
+	Status string
+	Reason string
+}
+
+// checkXMLResponse checks the status of an XML response, e.g. <status>SUCCESS</status>.
+// The response consists of sibling elements without a root element.
+func checkXMLResponse(body io.Reader) error {
+	var res xmlResponse
+	dec := xml.NewDecoder(body)
+	var element string
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = t.Name.Local
+		case xml.EndElement:
+			element = ""
+		case xml.CharData:
+			switch element {
+			case "status":
+				res.Status += string(t)
+			case "reason":
+				res.Reason += string(t)
+			}
+		}
+	}
+
+	if res.Status != statusSuccess {
+		return &FailureError{Status: res.Status, Reason: res.Reason}
+	}
+	return nil
+}
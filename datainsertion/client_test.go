@@ -0,0 +1,261 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datainsertion_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/datainsertion"
+	"github.com/adobe/aa-client-go/datainsertion/datainsertiontest"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		baseURL    string
+		maxRetries int
+		err        string
+	}{
+		{"https://namespace.sc.omtrdc.net/", 0, ""},
+		{"https://namespace.sc.omtrdc.net/", 100, ""},
+		{"namespace.sc.omtrdc.net", 0, "missing URL scheme"},
+		{"https://", 0, "missing URL host"},
+		{"https://namespace.sc.omtrdc.net/", -1, "negative MaxRetries"},
+	}
+
+	for _, test := range tests {
+		_, err := datainsertion.NewClient(&datainsertion.Config{BaseURL: test.baseURL, MaxRetries: test.maxRetries})
+		if test.err == "" && err != nil {
+			t.Errorf("Unexpected error for %s: %v", test.baseURL, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("Expected error %q for %s but got %v", test.err, test.baseURL, err)
+		}
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	hit := newHit()
+	err := client.Send(hit)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	hits := server.Hits()
+	if len(hits) != 1 {
+		t.Fatalf("Expected %d hits but got %d", 1, len(hits))
+	}
+	if !reflect.DeepEqual(hits[0], hit) {
+		t.Errorf("Expected hit %+v but got %+v", hit, hits[0])
+	}
+}
+
+func TestClientSendGet(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	hit := newHit()
+	hit.IPAddress = "192.0.2.1"
+	hit.UserAgent = "Mozilla/5.0"
+	hit.LinkType = datainsertion.LinkTypeExit
+	hit.LinkURL = "https://example.org/"
+	err := client.SendGet(hit)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	hits := server.Hits()
+	if len(hits) != 1 {
+		t.Fatalf("Expected %d hits but got %d", 1, len(hits))
+	}
+	if !reflect.DeepEqual(hits[0], hit) {
+		t.Errorf("Expected hit %+v but got %+v", hit, hits[0])
+	}
+}
+
+func TestClientSendInvalid(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	hit := newHit()
+	hit.ReportSuiteID = ""
+	err := client.Send(hit)
+	if err == nil || err.Error() != "missing ReportSuiteID" {
+		t.Errorf("Expected validation error but got %v", err)
+	}
+	if server.Requests() != 0 {
+		t.Errorf("Expected %d requests but got %d", 0, server.Requests())
+	}
+}
+
+func TestClientSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><status>FAILURE</status><reason>NO pagename OR pageurl</reason>`))
+	}))
+	defer server.Close()
+	client, err := datainsertion.NewClient(&datainsertion.Config{BaseURL: server.URL, MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	err = client.Send(newHit())
+	var failure *datainsertion.FailureError
+	if !errors.As(err, &failure) {
+		t.Fatalf("Expected FailureError but got %v", err)
+	}
+	if failure.Status != "FAILURE" || failure.Reason != "NO pagename OR pageurl" {
+		t.Errorf("Unexpected failure %+v", failure)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+	config := server.Config()
+	config.MaxRetries = 2
+	client, err := datainsertion.NewClient(config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	server.AddFault(datainsertiontest.Fault{Times: 1, StatusCode: http.StatusServiceUnavailable})
+	server.AddFault(datainsertiontest.Fault{Times: 1, StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}})
+	err = client.Send(newHit())
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("Expected %d requests but got %d", 3, server.Requests())
+	}
+
+	server.AddFault(datainsertiontest.Fault{StatusCode: http.StatusBadGateway})
+	err = client.Send(newHit())
+	if err == nil || err.Error() != "received unexpected status code 502" {
+		t.Errorf("Expected status code error but got %v", err)
+	}
+	if server.Requests() != 6 {
+		t.Errorf("Expected %d requests but got %d", 6, server.Requests())
+	}
+	server.ClearFaults()
+
+	server.AddFault(datainsertiontest.Fault{Times: 1, StatusCode: http.StatusBadRequest})
+	err = client.Send(newHit())
+	if err == nil || server.Requests() != 7 {
+		t.Errorf("Expected no retry of status code 400 but got %v after %d requests", err, server.Requests())
+	}
+
+	config.RetryWait = time.Hour
+	client, _ = datainsertion.NewClient(config)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	server.AddFault(datainsertiontest.Fault{StatusCode: http.StatusServiceUnavailable})
+	err = client.WithContext(ctx).Send(newHit())
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestClientRetryNetworkErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Error: %v", err)
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	config := &datainsertion.Config{BaseURL: server.URL, MaxRetries: 2, RetryWait: time.Millisecond}
+	client, err := datainsertion.NewClient(config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if err := client.Send(newHit()); err == nil {
+		t.Errorf("Expected network error but got none")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected %d requests but got %d", 1, n)
+	}
+
+	config.RetryNetworkErrors = true
+	client, _ = datainsertion.NewClient(config)
+	if err := client.Send(newHit()); err == nil {
+		t.Errorf("Expected network error but got none")
+	}
+	if n := atomic.LoadInt32(&requests); n != 4 {
+		t.Errorf("Expected %d requests but got %d", 4, n)
+	}
+}
+
+func TestClientSendBatch(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+	config := server.Config()
+	config.Concurrency = 4
+	client, err := datainsertion.NewClient(config)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var hits []*datainsertion.Hit
+	for i := 0; i < 20; i++ {
+		hits = append(hits, &datainsertion.Hit{
+			ReportSuiteID: "rsid",
+			VisitorID:     strconv.Itoa(i % 3),
+			PageName:      strconv.Itoa(i),
+		})
+	}
+	hits[5].PageName = ""
+
+	results := client.SendBatch(hits)
+	if len(results) != len(hits) {
+		t.Fatalf("Expected %d results but got %d", len(hits), len(results))
+	}
+	for i, result := range results {
+		if result.Hit != hits[i] {
+			t.Errorf("Expected result %d for hit %d", i, i)
+		}
+		if (result.Err != nil) != (i == 5) {
+			t.Errorf("Unexpected error of hit %d: %v", i, result.Err)
+		}
+	}
+
+	// hits of a visitor are received in order
+	last := map[string]int{}
+	received := server.Hits()
+	if len(received) != 19 {
+		t.Fatalf("Expected %d hits but got %d", 19, len(received))
+	}
+	for _, hit := range received {
+		n, _ := strconv.Atoi(hit.PageName)
+		if previous, ok := last[hit.VisitorID]; ok && previous > n {
+			t.Errorf("Expected hit %d of visitor %s after hit %d", n, hit.VisitorID, previous)
+		}
+		last[hit.VisitorID] = n
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package datainsertiontest provides a local stand-in of the Data Insertion API for tests.
+package datainsertiontest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/aa-client-go/datainsertion"
+)
+
+// Fault represents a fault injected into the responses of a Server.
+// A fault applies to the next Times requests, or to all requests if Times is 0.
+// The response is replaced by StatusCode, e.g. 429 or 503.
+type Fault struct {
+	Times      int
+	StatusCode int
+	Header     http.Header
+}
+
+// Server is a stand-in of a tracking server which accepts Data Insertion API hits.
+// It validates the received hits and responds like the Data Insertion API, invalid hits fail with a reason.
+type Server struct {
+	// URL is the base URL of the server, e.g. for Config.BaseURL
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	hits     []*datainsertion.Hit
+	requests int
+	faults   []*Fault
+}
+
+// NewServer starts and returns a new Server, it should be closed when finished.
+func NewServer() *Server {
+	s := &Server{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a client configuration for the server, retries wait for one millisecond.
+func (s *Server) Config() *datainsertion.Config {
+	return &datainsertion.Config{
+		HTTPClient: s.server.Client(),
+		BaseURL:    s.URL,
+		RetryWait:  time.Millisecond,
+	}
+}
+
+// Client returns a client for the server.
+func (s *Server) Client() *datainsertion.Client {
+	client, err := datainsertion.NewClient(s.Config())
+	if err != nil {
+		// the configuration is always valid
+		panic(err)
+	}
+	return client
+}
+
+// AddFault injects a fault.
+func (s *Server) AddFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Hits returns the accepted hits in the order received.
+func (s *Server) Hits() []*datainsertion.Hit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*datainsertion.Hit{}, s.hits...)
+}
+
+// Requests returns the number of received requests, including retries and rejected hits.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// serveHTTP applies faults and serves XML POST and GET requests.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	fault := s.fault()
+	s.mu.Unlock()
+
+	if fault != nil {
+		for key, values := range fault.Header {
+			w.Header()[key] = values
+		}
+		http.Error(w, http.StatusText(fault.StatusCode), fault.StatusCode)
+		return
+	}
+
+	var hit *datainsertion.Hit
+	var err error
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/b/ss//6":
+		hit, err = parseXML(r.Body)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/b/ss/") && strings.HasSuffix(r.URL.Path, "/0"):
+		hit, err = parseQuery(r)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err == nil {
+		err = hit.Validate()
+	}
+	if err != nil {
+		writeStatus(w, "FAILURE", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.hits = append(s.hits, hit)
+	s.mu.Unlock()
+	writeStatus(w, "SUCCESS", "")
+}
+
+// fault returns the next fault and counts its use, the caller must hold the lock.
+func (s *Server) fault() *Fault {
+	if len(s.faults) == 0 {
+		return nil
+	}
+	fault := s.faults[0]
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			s.faults = s.faults[1:]
+		}
+	}
+	return fault
+}
+
+// writeStatus writes an XML status response
+func writeStatus(w http.ResponseWriter, status, reason string) {
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, "%s<status>%s</status>", xml.Header, status)
+	if reason != "" {
+		fmt.Fprint(w, "<reason>")
+		xml.EscapeText(w, []byte(reason))
+		fmt.Fprint(w, "</reason>")
+	}
+}
+
+// params maps query parameters of GET requests to XML elements
+var params = map[string]string{
+	"vid":        "visitorID",
+	"mid":        "marketingCloudVisitorID",
+	"pageName":   "pageName",
+	"g":          "pageURL",
+	"r":          "referrer",
+	"ch":         "channel",
+	"events":     "events",
+	"products":   "products",
+	"purchaseID": "purchaseID",
+	"pe":         "linkType",
+	"pev2":       "linkName",
+	"pev1":       "linkURL",
+	"ts":         "timestamp",
+}
+
+// parseXML parses a hit from an XML request
+func parseXML(body io.Reader) (*datainsertion.Hit, error) {
+	var request struct {
+		Fields []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := xml.NewDecoder(body).Decode(&request); err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, field := range request.Fields {
+		values[field.XMLName.Local] = field.Value
+	}
+	return newHit(values)
+}
+
+// parseQuery parses a hit from a GET request, the report suite ID is part of the path
+func parseQuery(r *http.Request) (*datainsertion.Hit, error) {
+	rsid, err := url.PathUnescape(strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), "/b/ss/"), "/0"))
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{
+		"reportSuiteID": rsid,
+		"IPAddress":     r.Header.Get("X-Forwarded-For"),
+		"userAgent":     r.Header.Get("User-Agent"),
+	}
+	for param, v := range r.URL.Query() {
+		element, ok := params[param]
+		switch {
+		case ok:
+		case strings.HasPrefix(param, "v"):
+			element = "eVar" + strings.TrimPrefix(param, "v")
+		case strings.HasPrefix(param, "c"):
+			element = "prop" + strings.TrimPrefix(param, "c")
+		default:
+			continue
+		}
+		values[element] = v[0]
+	}
+	values["linkType"] = strings.TrimPrefix(values["linkType"], "lnk_")
+	return newHit(values)
+}
+
+// newHit returns a hit with the values of XML elements
+func newHit(values map[string]string) (*datainsertion.Hit, error) {
+	hit := &datainsertion.Hit{
+		ReportSuiteID:           values["reportSuiteID"],
+		VisitorID:               values["visitorID"],
+		MarketingCloudVisitorID: values["marketingCloudVisitorID"],
+		IPAddress:               values["IPAddress"],
+		UserAgent:               values["userAgent"],
+		PageName:                values["pageName"],
+		PageURL:                 values["pageURL"],
+		Referrer:                values["referrer"],
+		Channel:                 values["channel"],
+		Products:                values["products"],
+		PurchaseID:              values["purchaseID"],
+		LinkType:                values["linkType"],
+		LinkName:                values["linkName"],
+		LinkURL:                 values["linkURL"],
+	}
+	if events := values["events"]; events != "" {
+		hit.Events = strings.Split(events, ",")
+	}
+	if ts := values["timestamp"]; ts != "" {
+		seconds, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", ts)
+		}
+		hit.Timestamp = time.Unix(seconds, 0).UTC()
+	}
+
+	for name, value := range values {
+		var prefix string
+		var vars *map[int]string
+		switch {
+		case strings.HasPrefix(name, "eVar"):
+			prefix, vars = "eVar", &hit.EVars
+		case strings.HasPrefix(name, "prop"):
+			prefix, vars = "prop", &hit.Props
+		default:
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", name)
+		}
+		if *vars == nil {
+			*vars = map[int]string{}
+		}
+		(*vars)[n] = value
+	}
+	return hit, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datainsertiontest_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adobe/aa-client-go/datainsertion/datainsertiontest"
+)
+
+func TestServerRejectsInvalidHits(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		reason string
+	}{
+		{http.MethodPost, "/b/ss//6", `<request><visitorID>1</visitorID><pageName>p</pageName></request>`, "missing ReportSuiteID"},
+		{http.MethodPost, "/b/ss//6", `<request><reportSuiteID>rsid</reportSuiteID><visitorID>1</visitorID><pageName>p</pageName><eVarX>x</eVarX></request>`, "invalid eVarX"},
+		{http.MethodGet, "/b/ss/rsid/0?vid=1", "", "missing PageName or PageURL"},
+		{http.MethodGet, "/b/ss/rsid/0?vid=1&pageName=p&ts=yesterday", "", `invalid timestamp "yesterday"`},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		expected := "<status>FAILURE</status><reason>" + strings.Replace(test.reason, `"`, "&#34;", -1) + "</reason>"
+		if !strings.HasSuffix(string(body), expected) {
+			t.Errorf("Expected response %s but got %s", expected, body)
+		}
+	}
+
+	if len(server.Hits()) != 0 || server.Requests() != len(tests) {
+		t.Errorf("Expected %d requests without hits but got %d requests and %d hits", len(tests), server.Requests(), len(server.Hits()))
+	}
+}
+
+func TestServerNotFound(t *testing.T) {
+	server := datainsertiontest.NewServer()
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/b/ss/rsid/1")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, res.StatusCode)
+	}
+}
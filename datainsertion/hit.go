@@ -0,0 +1,218 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datainsertion
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxEVar is the highest eVar number
+	MaxEVar = 250
+	// MaxProp is the highest prop number
+	MaxProp = 75
+)
+
+// Link types of link tracking hits
+const (
+	LinkTypeCustom   = "o"
+	LinkTypeDownload = "d"
+	LinkTypeExit     = "e"
+)
+
+// Hit represents a hit sent to the Data Insertion API.
+// A hit is a page view, unless LinkType is set.
+type Hit struct {
+	ReportSuiteID string
+	// VisitorID is a custom visitor ID, alternatively MarketingCloudVisitorID or IPAddress identify the visitor.
+	VisitorID               string
+	MarketingCloudVisitorID string
+	IPAddress               string
+	UserAgent               string
+
+	PageName string
+	PageURL  string
+	Referrer string
+	Channel  string
+
+	// Events are the events of the hit, e.g. "purchase" or "event1=2".
+	Events     []string
+	Products   string
+	PurchaseID string
+	// EVars maps eVar numbers to values, e.g. 1 to the value of eVar1.
+	EVars map[int]string
+	// Props maps prop numbers to values, e.g. 1 to the value of prop1.
+	Props map[int]string
+
+	LinkType string
+	LinkName string
+	LinkURL  string
+
+	// Timestamp is the time of the hit, it is required by timestamp-enabled report suites.
+	Timestamp time.Time
+}
+
+// Validate checks the hit for missing or invalid values.
+func (hit *Hit) Validate() error {
+	if hit.ReportSuiteID == "" {
+		return fmt.Errorf("missing ReportSuiteID")
+	}
+	if hit.VisitorID == "" && hit.MarketingCloudVisitorID == "" && hit.IPAddress == "" {
+		return fmt.Errorf("missing VisitorID, MarketingCloudVisitorID or IPAddress")
+	}
+	switch hit.LinkType {
+	case "":
+		if hit.PageName == "" && hit.PageURL == "" {
+			return fmt.Errorf("missing PageName or PageURL")
+		}
+	case LinkTypeCustom, LinkTypeDownload, LinkTypeExit:
+	default:
+		return fmt.Errorf("invalid LinkType %q", hit.LinkType)
+	}
+	for n := range hit.EVars {
+		if n < 1 || n > MaxEVar {
+			return fmt.Errorf("invalid eVar%d", n)
+		}
+	}
+	for n := range hit.Props {
+		if n < 1 || n > MaxProp {
+			return fmt.Errorf("invalid prop%d", n)
+		}
+	}
+	for _, event := range hit.Events {
+		if event == "" || strings.Contains(event, ",") {
+			return fmt.Errorf("invalid event %q", event)
+		}
+	}
+	return nil
+}
+
+// field is a value of a hit with its XML element and query parameter names
+type field struct {
+	element string
+	param   string
+	value   string
+}
+
+// fields returns the non-empty values of the hit.
+// Values without query parameter name are sent as headers by GET requests.
+func (hit *Hit) fields() []field {
+	fields := []field{
+		{"reportSuiteID", "", hit.ReportSuiteID},
+		{"visitorID", "vid", hit.VisitorID},
+		{"marketingCloudVisitorID", "mid", hit.MarketingCloudVisitorID},
+		{"IPAddress", "", hit.IPAddress},
+		{"userAgent", "", hit.UserAgent},
+		{"pageName", "pageName", hit.PageName},
+		{"pageURL", "g", hit.PageURL},
+		{"referrer", "r", hit.Referrer},
+		{"channel", "ch", hit.Channel},
+		{"events", "events", strings.Join(hit.Events, ",")},
+		{"products", "products", hit.Products},
+		{"purchaseID", "purchaseID", hit.PurchaseID},
+	}
+	for _, n := range sortedKeys(hit.EVars) {
+		fields = append(fields, field{"eVar" + strconv.Itoa(n), "v" + strconv.Itoa(n), hit.EVars[n]})
+	}
+	for _, n := range sortedKeys(hit.Props) {
+		fields = append(fields, field{"prop" + strconv.Itoa(n), "c" + strconv.Itoa(n), hit.Props[n]})
+	}
+	if hit.LinkType != "" {
+		fields = append(fields,
+			field{"linkType", "pe", hit.LinkType},
+			field{"linkName", "pev2", hit.LinkName},
+			field{"linkURL", "pev1", hit.LinkURL},
+		)
+	}
+	if !hit.Timestamp.IsZero() {
+		fields = append(fields, field{"timestamp", "ts", strconv.FormatInt(hit.Timestamp.Unix(), 10)})
+	}
+
+	nonEmpty := fields[:0]
+	for _, f := range fields {
+		if f.value != "" {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	return nonEmpty
+}
+
+// Field represents a value of a hit with the name of its XML element, e.g. "eVar1"
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Fields returns the non-empty values of the hit in the order of the XML request.
+func (hit *Hit) Fields() []Field {
+	var fields []Field
+	for _, f := range hit.fields() {
+		fields = append(fields, Field{Name: f.element, Value: f.value})
+	}
+	return fields
+}
+
+// XML returns the hit as XML request of the Data Insertion API.
+func (hit *Hit) XML() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	request := xml.StartElement{Name: xml.Name{Local: "request"}}
+	if err := enc.EncodeToken(request); err != nil {
+		return nil, err
+	}
+	for _, f := range hit.fields() {
+		if err := enc.EncodeElement(f.value, xml.StartElement{Name: xml.Name{Local: f.element}}); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.EncodeToken(request.End()); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Query returns the hit as query parameters of a GET request of the Data Insertion API.
+// IPAddress and UserAgent are sent as headers and not part of the query.
+func (hit *Hit) Query() url.Values {
+	query := url.Values{}
+	for _, f := range hit.fields() {
+		switch f.param {
+		case "":
+		case "pe":
+			query.Set(f.param, "lnk_"+f.value)
+		default:
+			query.Set(f.param, f.value)
+		}
+	}
+	return query
+}
+
+// sortedKeys returns the sorted keys of the map
+func sortedKeys(m map[int]string) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
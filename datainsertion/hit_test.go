@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package datainsertion_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/aa-client-go/datainsertion"
+)
+
+func newHit() *datainsertion.Hit {
+	return &datainsertion.Hit{
+		ReportSuiteID: "rsid",
+		VisitorID:     "123",
+		PageName:      "Checkout & Pay",
+		Events:        []string{"purchase", "event1=2"},
+		EVars:         map[int]string{10: "ten", 2: "two"},
+		Props:         map[int]string{1: "one"},
+		Timestamp:     time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHitValidate(t *testing.T) {
+	tests := []struct {
+		modify func(hit *datainsertion.Hit)
+		err    string
+	}{
+		{func(hit *datainsertion.Hit) {}, ""},
+		{func(hit *datainsertion.Hit) { hit.ReportSuiteID = "" }, "missing ReportSuiteID"},
+		{func(hit *datainsertion.Hit) { hit.VisitorID = "" }, "missing VisitorID, MarketingCloudVisitorID or IPAddress"},
+		{func(hit *datainsertion.Hit) { hit.VisitorID, hit.IPAddress = "", "192.0.2.1" }, ""},
+		{func(hit *datainsertion.Hit) { hit.PageName = "" }, "missing PageName or PageURL"},
+		{func(hit *datainsertion.Hit) { hit.PageName, hit.LinkType = "", datainsertion.LinkTypeCustom }, ""},
+		{func(hit *datainsertion.Hit) { hit.LinkType = "x" }, `invalid LinkType "x"`},
+		{func(hit *datainsertion.Hit) { hit.EVars[251] = "x" }, "invalid eVar251"},
+		{func(hit *datainsertion.Hit) { hit.Props[0] = "x" }, "invalid prop0"},
+		{func(hit *datainsertion.Hit) { hit.Events = []string{"event1,event2"} }, `invalid event "event1,event2"`},
+	}
+
+	for i, test := range tests {
+		hit := newHit()
+		test.modify(hit)
+		err := hit.Validate()
+		if test.err == "" && err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("Test %d: Expected error %q but got %v", i, test.err, err)
+		}
+	}
+}
+
+func TestHitXML(t *testing.T) {
+	data, err := newHit().XML()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `<request><reportSuiteID>rsid</reportSuiteID><visitorID>123</visitorID><pageName>Checkout &amp; Pay</pageName>` +
+		`<events>purchase,event1=2</events><eVar2>two</eVar2><eVar10>ten</eVar10><prop1>one</prop1><timestamp>1585735200</timestamp></request>`
+	if !strings.HasPrefix(string(data), "<?xml") || !strings.HasSuffix(string(data), expected) {
+		t.Errorf("Expected XML %s but got %s", expected, data)
+	}
+}
+
+func TestHitQuery(t *testing.T) {
+	hit := newHit()
+	hit.IPAddress = "192.0.2.1"
+	hit.LinkType = datainsertion.LinkTypeDownload
+	hit.LinkURL = "https://example.com/file.pdf"
+
+	expected := "c1=one&events=purchase%2Cevent1%3D2&pageName=Checkout+%26+Pay&pe=lnk_d&pev1=https%3A%2F%2Fexample.com%2Ffile.pdf&ts=1585735200&v10=ten&v2=two&vid=123"
+	if query := hit.Query().Encode(); query != expected {
+		t.Errorf("Expected query %s but got %s", expected, query)
+	}
+}
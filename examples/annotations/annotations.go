@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/examples/config"
+	"github.com/adobe/aa-client-go/examples/utils"
+)
+
+func main() {
+	// Read configuration
+	cfg, err := config.Read()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create an Analytics client
+	client, err := utils.NewAnalyticsClient(&cfg.Analytics)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Annotate today's deployment
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	annotation, err := client.Annotations.Create(&analytics.Annotation{
+		Name:              "Release <VERSION>",
+		Description:       "Deployed <VERSION>",
+		RSID:              "<ReportSuiteID>",
+		Color:             analytics.AnnotationColorStandard3,
+		ApplyToAllReports: analytics.Bool(true),
+		DateRange: &analytics.DateInterval{
+			Start: start,
+			End:   start.Add(24*time.Hour - time.Millisecond),
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	utils.PrintJSON(annotation)
+
+	// Get annotations of the report suite
+	annotations, err := client.Annotations.GetAll("<ReportSuiteID>", "", "", nil, 100, 0, "", "", []string{}, []string{"all"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	utils.PrintJSON(annotations)
+}
@@ -10,3 +10,6 @@ require (
 	github.com/tkanos/gonfig v0.0.0-20181112185242-896f3d81fadf
 	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
+
+// the examples use the client of this repository
+replace github.com/adobe/aa-client-go => ../
@@ -0,0 +1,23 @@
+module github.com/adobe/aa-client-go/otelanalytics
+
+go 1.21
+
+require (
+	github.com/adobe/aa-client-go v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/metric v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/sdk/metric v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
+
+// The client is resolved from this repository during development, the replace directive is
+// ignored by consumers. Releases require the tagged client version, see `make otel-require`.
+replace github.com/adobe/aa-client-go => ../
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package otelanalytics provides OpenTelemetry instrumentation for the Analytics API 2.0 client.
+package otelanalytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer and meter
+const instrumentationName = "github.com/adobe/aa-client-go/otelanalytics"
+
+// Attribute keys of spans and metrics
+const (
+	ServiceKey    = attribute.Key("analytics.service")
+	OperationKey  = attribute.Key("analytics.operation")
+	RSIDKey       = attribute.Key("analytics.rsid")
+	RetriesKey    = attribute.Key("analytics.retries")
+	MethodKey     = attribute.Key("http.request.method")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// config holds the instrumentation options
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider, defaults to the global TracerProvider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tracerProvider
+	}
+}
+
+// WithMeterProvider sets the MeterProvider, defaults to the global MeterProvider.
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = meterProvider
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into the request headers, defaults to the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Middleware returns an analytics.Middleware which creates a client span per API request,
+// injects the trace context into the request headers and records the metrics
+// analytics.client.requests, analytics.client.errors and analytics.client.duration.
+// The span is a child of the span in the client context, see analytics.Client.WithContext.
+// Use Transport to record retries done by the HTTP client.
+func Middleware(opts ...Option) (analytics.Middleware, error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	requests, err := meter.Int64Counter("analytics.client.requests",
+		metric.WithDescription("Number of API requests"))
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter("analytics.client.errors",
+		metric.WithDescription("Number of failed API requests"))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram("analytics.client.duration",
+		metric.WithDescription("Duration of API requests"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next analytics.Handler) analytics.Handler {
+		return func(req *analytics.Request) (*http.Response, error) {
+			service, operation := operation(req.Method, req.Path)
+			attrs := []attribute.KeyValue{
+				ServiceKey.String(service),
+				OperationKey.String(operation),
+				MethodKey.String(req.Method),
+			}
+			if rsid := reportSuiteID(req); rsid != "" {
+				attrs = append(attrs, RSIDKey.String(rsid))
+			}
+
+			ctx := req.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			ctx, span := tracer.Start(ctx, "analytics "+operation,
+				trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			attempts := new(int64)
+			req.Context = context.WithValue(ctx, attemptsKey{}, attempts)
+			c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			start := time.Now()
+			res, err := next(req)
+			elapsed := time.Since(start).Seconds()
+
+			if n := atomic.LoadInt64(attempts); n > 0 {
+				span.SetAttributes(RetriesKey.Int64(n - 1))
+			}
+
+			failed := err != nil
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				attrs = append(attrs, StatusCodeKey.Int(res.StatusCode))
+				span.SetAttributes(StatusCodeKey.Int(res.StatusCode))
+				if res.StatusCode >= 400 {
+					failed = true
+					span.SetStatus(codes.Error, fmt.Sprintf("status code %d", res.StatusCode))
+				}
+			}
+
+			set := metric.WithAttributes(attrs...)
+			requests.Add(ctx, 1, set)
+			duration.Record(ctx, elapsed, set)
+			if failed {
+				errors.Add(ctx, 1, set)
+			}
+			return res, err
+		}
+	}, nil
+}
+
+// attemptsKey is the context key of the request attempt counter
+type attemptsKey struct{}
+
+// transport is a http.RoundTripper counting the attempts of a request
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport returns a http.RoundTripper which counts the attempts of each API request, e.g. to record retries.
+// It must wrap the innermost transport of a retrying HTTP client, http.DefaultTransport is used if base is nil.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+// RoundTrip counts the attempt and adds a retry event to the request span.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if attempts, ok := req.Context().Value(attemptsKey{}).(*int64); ok {
+		if n := atomic.AddInt64(attempts, 1); n > 1 {
+			span := trace.SpanFromContext(req.Context())
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int64("analytics.attempt", n)))
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// operation returns service and operation of a request, e.g. "dateranges" and "GET /dateranges/{id}".
+// Path segments containing digits are considered IDs.
+func operation(method, path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+
+	service := segments[0]
+	if service == "componentmetadata" && len(segments) > 1 {
+		// e.g. "/componentmetadata/tags"
+		service = segments[1]
+	}
+	return service, method + " /" + strings.Join(segments, "/")
+}
+
+// reportSuiteID returns the report suite ID of a request, from the params or the JSON body.
+func reportSuiteID(req *analytics.Request) string {
+	for _, key := range []string{"rsid", "rsids"} {
+		if rsid, ok := req.Params[key]; ok {
+			return rsid
+		}
+	}
+
+	if !bytes.Contains(req.Body, []byte(`"rsid"`)) {
+		return ""
+	}
+	var body struct {
+		ReportSuiteID string `json:"rsid"`
+	}
+	json.Unmarshal(req.Body, &body)
+	return body.ReportSuiteID
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package otelanalytics_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/otelanalytics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// retryTransport retries requests once on status code 500
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err == nil && res.StatusCode == http.StatusInternalServerError {
+		res.Body.Close()
+		return t.base.RoundTrip(req)
+	}
+	return res, err
+}
+
+func newTestClient(t *testing.T, serverURL string, opts ...otelanalytics.Option) *analytics.Client {
+	middleware, err := otelanalytics.Middleware(opts...)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	client, err := analytics.NewClient(&analytics.Config{
+		HTTPClient:  &http.Client{Transport: &retryTransport{base: otelanalytics.Transport(nil)}},
+		BaseURL:     serverURL + "/api",
+		ClientID:    "imsClientId",
+		OrgID:       "imsOrgId",
+		AccessToken: "imsAuthToken",
+		CompanyID:   "aaCompanyId",
+		Middlewares: []analytics.Middleware{middleware},
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return client
+}
+
+func TestMiddleware(t *testing.T) {
+	attempts := 0
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		traceparent = r.Header.Get("traceparent")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"totalPages": 1, "rows": []}`)
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := newTestClient(t, server.URL,
+		otelanalytics.WithTracerProvider(tracerProvider),
+		otelanalytics.WithMeterProvider(meterProvider),
+		otelanalytics.WithPropagator(propagation.TraceContext{}))
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.WithContext(ctx).Reports.Run(&analytics.RankedRequest{
+		ReportSuiteID: "amc.aem.prod",
+		Dimension:     "variables/page",
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	parent.End()
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("Expected %d spans but got %d", 2, len(ended))
+	}
+
+	span := ended[0]
+	if span.Name() != "analytics POST /reports" {
+		t.Errorf("Expected span name %q but got %q", "analytics POST /reports", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected span to be a child of the parent span")
+	}
+	if !strings.Contains(traceparent, span.SpanContext().SpanID().String()) {
+		t.Errorf("Expected traceparent with span ID %s but got %q", span.SpanContext().SpanID(), traceparent)
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		otelanalytics.ServiceKey:    attribute.StringValue("reports"),
+		otelanalytics.OperationKey:  attribute.StringValue("POST /reports"),
+		otelanalytics.RSIDKey:       attribute.StringValue("amc.aem.prod"),
+		otelanalytics.RetriesKey:    attribute.Int64Value(1),
+		otelanalytics.StatusCodeKey: attribute.IntValue(200),
+	}
+	testAttributes(t, span.Attributes(), expected)
+	if len(span.Events()) != 1 || span.Events()[0].Name != "retry" {
+		t.Errorf("Expected a retry event but got %v", span.Events())
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					counts[m.Name] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					counts[m.Name] += int64(point.Count)
+				}
+			}
+		}
+	}
+	if counts["analytics.client.requests"] != 1 || counts["analytics.client.duration"] != 1 || counts["analytics.client.errors"] != 0 {
+		t.Errorf("Unexpected metrics %v", counts)
+	}
+}
+
+func TestMiddlewareError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := newTestClient(t, server.URL,
+		otelanalytics.WithTracerProvider(tracerProvider),
+		otelanalytics.WithMeterProvider(meterProvider))
+
+	_, err := client.DateRanges.GetByID("57a9ad685fe707f55ffb68f5", "", []string{})
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("Expected %d spans but got %d", 1, len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "analytics GET /dateranges/{id}" {
+		t.Errorf("Expected span name %q but got %q", "analytics GET /dateranges/{id}", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected span status %v but got %v", codes.Error, span.Status().Code)
+	}
+	testAttributes(t, span.Attributes(), map[attribute.Key]attribute.Value{
+		otelanalytics.ServiceKey:    attribute.StringValue("dateranges"),
+		otelanalytics.RetriesKey:    attribute.Int64Value(0),
+		otelanalytics.StatusCodeKey: attribute.IntValue(404),
+	})
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	found := false
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "analytics.client.errors" {
+				found = len(data.DataPoints) == 1 && data.DataPoints[0].Value == 1
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected an error to be recorded")
+	}
+}
+
+func testAttributes(t *testing.T, attrs []attribute.KeyValue, want map[attribute.Key]attribute.Value) {
+	got := map[attribute.Key]attribute.Value{}
+	for _, attr := range attrs {
+		got[attr.Key] = attr.Value
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("Attribute %s: %v, want %v", key, got[key].Emit(), value.Emit())
+		}
+	}
+}
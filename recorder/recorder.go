@@ -0,0 +1,295 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package recorder records HTTP interactions of the Analytics API 2.0 client into fixture files and replays them in tests.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+// Recorder modes
+const (
+	// ModeReplay replays recorded interactions, requests without recorded interaction fail.
+	ModeReplay Mode = iota
+	// ModeRecord sends all requests and records the interactions.
+	ModeRecord
+	// ModeReplayOrRecord replays recorded interactions and records the interactions of other requests.
+	ModeReplayOrRecord
+)
+
+// scrubbed replaces scrubbed header values
+const scrubbed = "[SCRUBBED]"
+
+// ScrubbedHeaders are the headers scrubbed from recorded interactions.
+var ScrubbedHeaders = []string{"Authorization", "X-Api-Key", "X-Gw-Ims-Org-Id", "Cookie", "Set-Cookie"}
+
+// RecordedRequest represents a recorded request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse represents a recorded response
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction represents a recorded request and response
+type Interaction struct {
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+// Cassette represents the recorded interactions of a fixture file
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is a http.RoundTripper recording and replaying interactions.
+type Recorder struct {
+	// Transport sends requests which are recorded, defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Filter optionally modifies interactions before they are recorded, e.g. to scrub IDs.
+	Filter func(*Interaction)
+
+	file string
+	mode Mode
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	changed  bool
+}
+
+// New returns a new Recorder for the fixture file.
+// Recorded interactions are loaded from the file, which is optional in ModeRecord and ModeReplayOrRecord.
+func New(file string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		file:     file,
+		mode:     mode,
+		cassette: &Cassette{},
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) && mode == ModeReplayOrRecord {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, r.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("malformed fixture file %s: %v", file, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Client returns a HTTP client using the recorder, e.g. for Config.HTTPClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop saves the recorded interactions to the fixture file, if any were recorded.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.changed {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(r.file), 0755)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(r.file, append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+	r.changed = false
+	return nil
+}
+
+// RoundTrip replays a recorded interaction of the request or sends and records it, depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode != ModeRecord {
+		if interaction := r.replay(req, body); interaction != nil {
+			return interaction.Response.response(req), nil
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+		}
+	}
+	return r.record(req, body)
+}
+
+// replay returns the first unused interaction matching the request or nil.
+func (r *Recorder) replay(req *http.Request, body []byte) *Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] && matches(interaction.Request, req, body) {
+			r.used[i] = true
+			return interaction
+		}
+	}
+	return nil
+}
+
+// record sends the request and records the interaction.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	interaction := &Interaction{
+		Request: &RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: scrubHeader(req.Header),
+			Body:   string(body),
+		},
+		Response: &RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     scrubHeader(res.Header),
+			Body:       string(resBody),
+		},
+	}
+	if r.Filter != nil {
+		r.Filter(interaction)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+	r.changed = true
+	return res, nil
+}
+
+// response returns the recorded response as HTTP response to the request.
+func (rr *RecordedResponse) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for key, values := range rr.Header {
+		header[key] = append([]string{}, values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(rr.Body)),
+		ContentLength: int64(len(rr.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the request body and replaces it for the transport.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// matches returns true if method, path, query and canonical JSON body of the recorded request match the request.
+func matches(recorded *RecordedRequest, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+
+	u, err := url.Parse(recorded.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+	if !reflect.DeepEqual(u.Query(), req.URL.Query()) {
+		return false
+	}
+	return canonicalBody([]byte(recorded.Body)) == canonicalBody(body)
+}
+
+// canonicalBody returns a JSON body with sorted keys and without whitespace, other bodies are returned as is.
+func canonicalBody(body []byte) string {
+	var data interface{}
+	if len(body) == 0 || json.Unmarshal(body, &data) != nil {
+		return string(body)
+	}
+	// maps are marshalled with sorted keys
+	canonical, _ := json.Marshal(data)
+	return string(canonical)
+}
+
+// scrubHeader returns a copy of the header with the values of the ScrubbedHeaders scrubbed.
+func scrubHeader(header http.Header) http.Header {
+	scrubbedHeader := http.Header{}
+	for key, values := range header {
+		scrubbedHeader[key] = append([]string{}, values...)
+	}
+	for _, key := range ScrubbedHeaders {
+		if scrubbedHeader.Get(key) != "" {
+			scrubbedHeader.Set(key, scrubbed)
+		}
+	}
+	return scrubbedHeader
+}
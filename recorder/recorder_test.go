@@ -0,0 +1,192 @@
+/*
+Copyright 2020 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package recorder_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adobe/aa-client-go/analytics"
+	"github.com/adobe/aa-client-go/recorder"
+)
+
+func newTestClient(t *testing.T, baseURL string, httpClient *http.Client) *analytics.Client {
+	client, err := analytics.NewClient(&analytics.Config{
+		HTTPClient:  httpClient,
+		BaseURL:     baseURL + "/api",
+		ClientID:    "imsClientId",
+		OrgID:       "imsOrgId",
+		AccessToken: "imsAuthToken",
+		CompanyID:   "aaCompanyId",
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return client
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "aa-client-go-recorder")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return dir
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "fixtures", "users.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/aaCompanyId/users/me":
+			fmt.Fprint(w, `{"login": "jdoe"}`)
+		case "/api/aaCompanyId/users":
+			fmt.Fprintf(w, `{"content": [{"login": "page%s"}]}`, r.URL.Query().Get("page"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	// record
+	rec, err := recorder.New(file, recorder.ModeRecord)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	client := newTestClient(t, server.URL, rec.Client())
+	client.Users.GetCurrent()
+	client.Users.GetAll(10, 0)
+	client.Users.GetAll(10, 1)
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	for _, secret := range []string{"imsAuthToken", "imsClientId", "imsOrgId"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be scrubbed from the fixture file", secret)
+		}
+	}
+
+	// replay, in a different order and against a different host
+	rec, err = recorder.New(file, recorder.ModeReplay)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	client = newTestClient(t, "http://localhost:1", rec.Client())
+
+	users, err := client.Users.GetAll(10, 1)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if (*users.Content)[0].Login != "page1" {
+		t.Errorf("Expected login %q but got %q", "page1", (*users.Content)[0].Login)
+	}
+	user, err := client.Users.GetCurrent()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if user.Login != "jdoe" {
+		t.Errorf("Expected login %q but got %q", "jdoe", user.Login)
+	}
+
+	// each interaction is replayed once
+	_, err = client.Users.GetCurrent()
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction for GET /api/aaCompanyId/users/me") {
+		t.Errorf("Expected missing interaction error but got %v", err)
+	}
+}
+
+func TestReplayCanonicalBody(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "reports.json")
+
+	fixture := `{
+  "interactions": [
+    {
+      "request": {"method": "POST", "url": "/api/aaCompanyId/reports?b=2&a=1", "body": "{\"rsid\": \"amc.aem.prod\", \"dimension\": \"variables/page\"}"},
+      "response": {"statusCode": 200, "body": "{\"totalPages\": 3}"}
+    }
+  ]
+}`
+	if err := ioutil.WriteFile(file, []byte(fixture), 0644); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	rec, err := recorder.New(file, recorder.ModeReplay)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	body := `{"dimension":"variables/page",
+		"rsid":"amc.aem.prod"}`
+	res, err := rec.Client().Post("http://localhost:1/api/aaCompanyId/reports?a=1&b=2", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+
+	data, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || string(data) != `{"totalPages": 3}` {
+		t.Errorf("Unexpected response %d %s", res.StatusCode, data)
+	}
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "users.json")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"login": "jdoe"}`)
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		rec, err := recorder.New(file, recorder.ModeReplayOrRecord)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		client := newTestClient(t, server.URL, rec.Client())
+		if _, err := client.Users.GetCurrent(); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if err := rec.Stop(); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected %d requests but got %d", 1, calls)
+	}
+}
+
+func TestNewMissingFixture(t *testing.T) {
+	_, err := recorder.New("./missing.json", recorder.ModeReplay)
+	if err == nil {
+		t.Errorf("Expected error but got none")
+	}
+}